@@ -0,0 +1,3 @@
+data "horizon_image_management_stream" "example" {
+  name = "Windows 11 Enterprise"
+}
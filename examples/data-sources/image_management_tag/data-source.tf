@@ -0,0 +1,4 @@
+data "horizon_image_management_tag" "example" {
+  name         = "production"
+  im_stream_id = data.horizon_image_management_stream.example.id
+}
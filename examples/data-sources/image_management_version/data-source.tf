@@ -0,0 +1,4 @@
+data "horizon_image_management_version" "example" {
+  name         = "1.0"
+  im_stream_id = data.horizon_image_management_stream.example.id
+}
@@ -0,0 +1,4 @@
+data "horizon_vcenter_customization_spec" "example" {
+  name       = "Windows 11 Sysprep"
+  vcenter_id = data.horizon_vcenter_server.example.id
+}
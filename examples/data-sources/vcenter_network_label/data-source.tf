@@ -0,0 +1,5 @@
+data "horizon_vcenter_network_label" "example" {
+  name               = "VDI-VLAN-100"
+  host_or_cluster_id = data.horizon_vcenter_host_or_cluster.example.id
+  vcenter_id         = data.horizon_vcenter_server.example.id
+}
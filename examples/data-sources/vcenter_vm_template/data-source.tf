@@ -0,0 +1,5 @@
+data "horizon_vcenter_vm_template" "example" {
+  name          = "tmpl-win11"
+  datacenter_id = data.horizon_vcenter_datacenter.example.id
+  vcenter_id    = data.horizon_vcenter_server.example.id
+}
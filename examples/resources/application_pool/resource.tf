@@ -0,0 +1,14 @@
+variable "farm_id" {
+  type = string
+}
+
+resource "horizon_application_pool" "notepad" {
+  name            = "notepad"
+  display_name    = "Notepad"
+  farm_id         = var.farm_id
+  executable_path = "C:\\Windows\\System32\\notepad.exe"
+  start_folder    = "C:\\Users\\Public\\Documents"
+
+  category_folder_name = "Accessories"
+  shortcut_locations   = ["START_MENU"]
+}
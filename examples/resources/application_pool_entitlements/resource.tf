@@ -0,0 +1,6 @@
+resource "horizon_application_pool_entitlements" "example" {
+  application_pool_id = horizon_application_pool.example.id
+  ad_user_or_group_ids = [
+    data.horizon_active_directory_domain_user_or_group.domain_users.id,
+  ]
+}
@@ -1,3 +1,110 @@
+data "horizon_local_access_group" "root" {
+  name = "Root"
+}
+
+data "horizon_vcenter_server" "vcenter" {
+  server_name = "vcenter.example.com"
+}
+
+data "horizon_vcenter_datacenter" "dc" {
+  name       = "Example Datacenter"
+  vcenter_id = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_host_or_cluster" "cluster" {
+  name          = "Example Cluster"
+  datacenter_id = data.horizon_vcenter_datacenter.dc.id
+  vcenter_id    = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_resource_pool" "pool" {
+  name               = "Example"
+  host_or_cluster_id = data.horizon_vcenter_host_or_cluster.cluster.id
+  vcenter_id         = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_vm_folder" "folder" {
+  path          = "/${data.horizon_vcenter_datacenter.dc.name}/vm/VDI"
+  datacenter_id = data.horizon_vcenter_datacenter.dc.id
+  vcenter_id    = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_datastore" "datastore" {
+  name               = "Example Datastore"
+  host_or_cluster_id = data.horizon_vcenter_host_or_cluster.cluster.id
+  vcenter_id         = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_vm_template" "win11" {
+  name          = "tmpl-win11"
+  datacenter_id = data.horizon_vcenter_datacenter.dc.id
+  vcenter_id    = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_customization_spec" "win11" {
+  name       = "Windows 11 Sysprep"
+  vcenter_id = data.horizon_vcenter_server.vcenter.id
+}
+
+# Full clone desktop pool built from a VM template and customized with Sysprep
 resource "horizon_desktop_pool_automated" "example" {
-  //TODO
+  name               = "full-clone-pool"
+  access_group_id    = data.horizon_local_access_group.root.id
+  vcenter_id         = data.horizon_vcenter_server.vcenter.id
+  source             = "VIRTUAL_CENTER"
+  naming_method      = "PATTERN"
+  user_assignment    = "DEDICATED"
+  customization_type = "SYS_PREP"
+
+  sys_prep_settings {
+    sysprep_customization_spec_id = data.horizon_vcenter_customization_spec.win11.id
+  }
+
+  pattern_naming_settings {
+    naming_pattern         = "vdi-{n:fixed=3}"
+    max_number_of_machines = 10
+  }
+
+  provisioning_settings {
+    datacenter_id      = data.horizon_vcenter_datacenter.dc.id
+    host_or_cluster_id = data.horizon_vcenter_host_or_cluster.cluster.id
+    resource_pool_id   = data.horizon_vcenter_resource_pool.pool.id
+    vm_folder_id       = data.horizon_vcenter_vm_folder.folder.id
+    vm_template_id     = data.horizon_vcenter_vm_template.win11.id
+  }
+
+  session_settings {
+    allow_users_to_reset_machines        = true
+    disconnected_session_timeout_policy  = "AFTER"
+    disconnected_session_timeout_minutes = 120
+    power_policy                         = "POWER_OFF"
+  }
+
+  storage_settings {
+    datastores {
+      datastore_id = data.horizon_vcenter_datastore.datastore.id
+    }
+    reclaim_vm_disk_space    = true
+    reclamation_threshold_mb = 1024
+  }
+
+  view_storage_accelerator_settings {
+    use_view_storage_accelerator             = true
+    regenerate_view_storage_accelerator_days = 7
+
+    blackout_times {
+      days       = ["MONDAY", "TUESDAY", "WEDNESDAY", "THURSDAY", "FRIDAY"]
+      start_time = "07:00"
+      end_time   = "18:00"
+    }
+  }
+
+  wait_for_provisioning {
+    condition     = "MACHINES_AVAILABLE"
+    machine_count = 2
+  }
+
+  timeouts {
+    create = "90m"
+  }
 }
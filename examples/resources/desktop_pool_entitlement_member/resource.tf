@@ -0,0 +1,4 @@
+resource "horizon_desktop_pool_entitlement_member" "example" {
+  pool_id             = horizon_desktop_pool_automated.example.id
+  ad_user_or_group_id = data.horizon_active_directory_domain_user_or_group.domain_users.id
+}
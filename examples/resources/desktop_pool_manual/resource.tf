@@ -0,0 +1,20 @@
+data "horizon_local_access_group" "root" {
+  name = "Root"
+}
+
+data "horizon_vcenter_server" "vcenter" {
+  server_name = "vcenter.example.com"
+}
+
+resource "horizon_desktop_pool_manual" "example" {
+  name            = "lab-pool"
+  access_group_id = data.horizon_local_access_group.root.id
+  source          = "VIRTUAL_CENTER"
+  vcenter_id      = data.horizon_vcenter_server.vcenter.id
+  user_assignment = "FLOATING"
+
+  vm_names = [
+    "lab-vm-01",
+    "lab-vm-02",
+  ]
+}
@@ -0,0 +1,11 @@
+variable "farm_id" {
+  type = string
+}
+
+resource "horizon_desktop_pool_rds" "example" {
+  name         = "rds-desktop"
+  display_name = "Session Desktop"
+  farm_id      = var.farm_id
+
+  allow_multiple_sessions_per_user = false
+}
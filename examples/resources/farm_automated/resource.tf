@@ -0,0 +1,93 @@
+data "horizon_local_access_group" "root" {
+  name = "Root"
+}
+
+data "horizon_vcenter_server" "vcenter" {
+  server_name = "vcenter.example.com"
+}
+
+data "horizon_vcenter_datacenter" "dc" {
+  name       = "Example Datacenter"
+  vcenter_id = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_host_or_cluster" "cluster" {
+  name          = "Example Cluster"
+  datacenter_id = data.horizon_vcenter_datacenter.dc.id
+  vcenter_id    = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_resource_pool" "pool" {
+  name               = "Example"
+  host_or_cluster_id = data.horizon_vcenter_host_or_cluster.cluster.id
+  vcenter_id         = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_vm_folder" "folder" {
+  path          = "/${data.horizon_vcenter_datacenter.dc.name}/vm/RDSH"
+  datacenter_id = data.horizon_vcenter_datacenter.dc.id
+  vcenter_id    = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_datastore" "datastore" {
+  name               = "Example Datastore"
+  host_or_cluster_id = data.horizon_vcenter_host_or_cluster.cluster.id
+  vcenter_id         = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_base_vm" "rdsh" {
+  name          = "rdsh-2022"
+  datacenter_id = data.horizon_vcenter_datacenter.dc.id
+  vcenter_id    = data.horizon_vcenter_server.vcenter.id
+}
+
+data "horizon_vcenter_base_vm_snapshot" "rdsh" {
+  base_vm_id = data.horizon_vcenter_base_vm.rdsh.id
+  path       = "/golden"
+  vcenter_id = data.horizon_vcenter_server.vcenter.id
+}
+
+variable "instant_clone_domain_account_id" {
+  type = string
+}
+
+resource "horizon_farm_automated" "example" {
+  name             = "rdsh-farm"
+  access_group_id  = data.horizon_local_access_group.root.id
+  vcenter_id       = data.horizon_vcenter_server.vcenter.id
+  max_session_type = "LIMITED"
+  max_sessions     = 20
+
+  clone_prep_settings {
+    ad_container_rdn                = "OU=RDSH,OU=Computers"
+    instant_clone_domain_account_id = var.instant_clone_domain_account_id
+  }
+
+  pattern_naming_settings {
+    naming_pattern            = "rdsh-{n:fixed=2}"
+    max_number_of_rds_servers = 4
+  }
+
+  provisioning_settings {
+    datacenter_id      = data.horizon_vcenter_datacenter.dc.id
+    host_or_cluster_id = data.horizon_vcenter_host_or_cluster.cluster.id
+    resource_pool_id   = data.horizon_vcenter_resource_pool.pool.id
+    vm_folder_id       = data.horizon_vcenter_vm_folder.folder.id
+    parent_vm_id       = data.horizon_vcenter_base_vm.rdsh.id
+    base_snapshot_id   = data.horizon_vcenter_base_vm_snapshot.rdsh.id
+  }
+
+  storage_settings {
+    datastores = [data.horizon_vcenter_datastore.datastore.id]
+  }
+
+  load_balancer_settings {
+    cpu_threshold    = 80
+    memory_threshold = 80
+  }
+
+  session_settings {
+    disconnected_session_timeout_policy  = "AFTER"
+    disconnected_session_timeout_minutes = 120
+  }
+}
@@ -0,0 +1,19 @@
+data "horizon_local_access_group" "root" {
+  name = "Root"
+}
+
+variable "rds_server_ids" {
+  type = list(string)
+}
+
+resource "horizon_farm_manual" "example" {
+  name            = "manual-farm"
+  access_group_id = data.horizon_local_access_group.root.id
+  rds_server_ids  = var.rds_server_ids
+
+  session_settings {
+    empty_session_timeout_policy  = "AFTER"
+    empty_session_timeout_minutes = 5
+    logoff_after_timeout          = true
+  }
+}
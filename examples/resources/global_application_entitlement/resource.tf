@@ -0,0 +1,12 @@
+variable "federated_access_group_id" {
+  type = string
+}
+
+resource "horizon_global_application_entitlement" "example" {
+  name                      = "global-notepad"
+  federated_access_group_id = var.federated_access_group_id
+  scope                     = "WITHIN_SITE"
+  local_application_pool_ids = [
+    horizon_application_pool.example.id,
+  ]
+}
@@ -0,0 +1,13 @@
+variable "federated_access_group_id" {
+  type = string
+}
+
+resource "horizon_global_desktop_entitlement" "example" {
+  name                      = "global-desktops"
+  federated_access_group_id = var.federated_access_group_id
+  scope                     = "ALL_SITES"
+  use_home_site             = true
+  local_desktop_pool_ids = [
+    horizon_desktop_pool_automated.example.id,
+  ]
+}
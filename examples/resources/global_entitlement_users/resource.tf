@@ -0,0 +1,15 @@
+resource "horizon_global_entitlement_users" "desktops" {
+  global_entitlement_id = horizon_global_desktop_entitlement.example.id
+  entitlement_type      = "DESKTOP"
+  ad_user_or_group_ids = [
+    data.horizon_active_directory_domain_user_or_group.domain_users.id,
+  ]
+}
+
+resource "horizon_global_entitlement_users" "notepad" {
+  global_entitlement_id = horizon_global_application_entitlement.example.id
+  entitlement_type      = "APPLICATION"
+  ad_user_or_group_ids = [
+    data.horizon_active_directory_domain_user_or_group.domain_users.id,
+  ]
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/umich-vci/gohorizon"
+)
+
+// tokenRefreshWindow is how long before it expires an access token is refreshed.
+const tokenRefreshWindow = 2 * time.Minute
+
+// horizonSession holds the tokens of a session logged in to a Horizon Connection Server.
+type horizonSession struct {
+	// authAPI must not use an authTransport for this session, otherwise refreshing would recurse.
+	authAPI *gohorizon.AuthApiService
+	// logoutAPI does not retry, so that logging out on shutdown is a single best-effort attempt.
+	// If it is nil authAPI is used.
+	logoutAPI *gohorizon.AuthApiService
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiry       time.Time
+}
+
+// newHorizonSession logs in to Horizon with the given credentials.
+func newHorizonSession(ctx context.Context, authAPI *gohorizon.AuthApiService, domain, username, password string) (*horizonSession, error) {
+	body := gohorizon.NewAuthLogin(domain, password, username)
+	tokens, _, err := authAPI.LoginUser(ctx).Body(*body).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	return &horizonSession{
+		authAPI:      authAPI,
+		accessToken:  tokens.GetAccessToken(),
+		refreshToken: tokens.GetRefreshToken(),
+		expiry:       tokenExpiry(tokens.GetAccessToken()),
+	}, nil
+}
+
+// token returns the current access token, refreshing it first if it is about to expire.
+func (s *horizonSession) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.expiry.IsZero() && time.Until(s.expiry) < tokenRefreshWindow {
+		if err := s.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return s.accessToken, nil
+}
+
+// refresh replaces a rejected access token. If another request already refreshed it, the new token is returned as is.
+func (s *horizonSession) refresh(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken == rejected {
+		if err := s.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return s.accessToken, nil
+}
+
+func (s *horizonSession) refreshLocked(ctx context.Context) error {
+	token, _, err := s.authAPI.RefreshAccessToken(ctx).Body(*gohorizon.NewRefreshToken(s.refreshToken)).Execute()
+	if err != nil {
+		return fmt.Errorf("error refreshing Horizon access token: %w", err)
+	}
+
+	s.accessToken = token.GetAccessToken()
+	s.expiry = tokenExpiry(s.accessToken)
+
+	return nil
+}
+
+// logout ends the session on the connection server.
+func (s *horizonSession) logout(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authAPI := s.authAPI
+	if s.logoutAPI != nil {
+		authAPI = s.logoutAPI
+	}
+	_, err := authAPI.LogoutUser(ctx).Body(*gohorizon.NewRefreshToken(s.refreshToken)).Execute()
+
+	return err
+}
+
+// tokenExpiry returns the expiry time of a JWT access token, or the zero time if it can not be determined.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
+
+// authTransport adds the access token of a session to requests, refreshing it when it is about to
+// expire and retrying a request once if it is rejected with a 401.
+type authTransport struct {
+	base    http.RoundTripper
+	session *horizonSession
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.session.token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withBearerToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// the request can only be sent again if its body can be replayed
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	token, err = t.session.refresh(req.Context(), token)
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := withBearerToken(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+
+	return t.base.RoundTrip(retry)
+}
+
+// withBearerToken returns a copy of the request with its Authorization header set to the token.
+func withBearerToken(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	return r
+}
+
+var sessions struct {
+	sync.Mutex
+	list []*horizonSession
+}
+
+// trackSession remembers a session so it can be logged out by Logout.
+func trackSession(s *horizonSession) {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	sessions.list = append(sessions.list, s)
+}
+
+// Logout logs out all of the Horizon sessions opened by the provider. It is called when the
+// provider shuts down so that sessions do not pile up on the connection server. Logging out is
+// best-effort: each session gets a single attempt within ctx, and sessions that could not be
+// logged out are left to expire on the connection server.
+func Logout(ctx context.Context) {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	for _, s := range sessions.list {
+		// there is nothing useful to do if the session has already expired
+		_ = s.logout(ctx)
+	}
+	sessions.list = nil
+}
@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/umich-vci/gohorizon"
+)
+
+// testHorizonSession logs in to a fake Horizon server.
+func testHorizonSession(t *testing.T, srv *httptest.Server) *horizonSession {
+	t.Helper()
+
+	client := gohorizon.NewAPIClient(newConfiguration(srv.Listener.Addr().String(), "test", srv.Client()))
+	session, err := newHorizonSession(context.Background(), client.AuthApi, "domain", "user", "password")
+	if err != nil {
+		t.Fatalf("error logging in: %s", err)
+	}
+
+	return session
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	payload := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	cases := []struct {
+		name  string
+		token string
+		want  time.Time
+	}{
+		{"valid", testJWT(exp), exp},
+		{"no exp claim", "e30." + payload(`{"sub":"user"}`) + ".c2ln", time.Time{}},
+		{"not a JWT", "opaque-token", time.Time{}},
+		{"invalid base64", "e30.!!!.c2ln", time.Time{}},
+		{"invalid JSON", "e30." + payload(`{"exp":`) + ".c2ln", time.Time{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tokenExpiry(tc.token); !got.Equal(tc.want) {
+				t.Errorf("tokenExpiry() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthTransportRefreshesExpiringToken(t *testing.T) {
+	expiring := testJWT(time.Now().Add(tokenRefreshWindow / 2))
+	fresh := testJWT(time.Now().Add(time.Hour))
+
+	var refreshes int32
+	var gotAuth string
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/login": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, map[string]string{"access_token": expiring, "refresh_token": "refresh"})
+		},
+		"/rest/refresh": func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&refreshes, 1)
+			writeTestJSON(w, http.StatusOK, map[string]string{"access_token": fresh})
+		},
+		"/rest/test": func(w http.ResponseWriter, r *http.Request) {
+			gotAuth = r.Header.Get("Authorization")
+		},
+	})
+
+	session := testHorizonSession(t, srv)
+	client := &http.Client{Transport: &authTransport{base: srv.Client().Transport, session: session}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL + "/rest/test")
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	if gotAuth != "Bearer "+fresh {
+		t.Errorf("Authorization = %q, want the refreshed token", gotAuth)
+	}
+	if refreshes != 1 {
+		t.Errorf("token refreshed %d times, want 1", refreshes)
+	}
+}
+
+func TestAuthTransportRetriesUnauthorized(t *testing.T) {
+	cases := []struct {
+		name          string
+		method        string
+		body          func() io.Reader
+		wantStatus    int
+		wantCalls     int32
+		wantRefreshes int32
+	}{
+		{
+			name:          "without body",
+			method:        http.MethodGet,
+			body:          func() io.Reader { return nil },
+			wantStatus:    http.StatusOK,
+			wantCalls:     2,
+			wantRefreshes: 1,
+		},
+		{
+			name:          "replayable body",
+			method:        http.MethodPost,
+			body:          func() io.Reader { return strings.NewReader("payload") },
+			wantStatus:    http.StatusOK,
+			wantCalls:     2,
+			wantRefreshes: 1,
+		},
+		{
+			name:          "body that can not be replayed",
+			method:        http.MethodPost,
+			body:          func() io.Reader { return io.NopCloser(strings.NewReader("payload")) },
+			wantStatus:    http.StatusUnauthorized,
+			wantCalls:     1,
+			wantRefreshes: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stale := testJWT(time.Now().Add(time.Hour))
+			fresh := testJWT(time.Now().Add(2 * time.Hour))
+
+			var calls, refreshes int32
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/login": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]string{"access_token": stale, "refresh_token": "refresh"})
+				},
+				"/rest/refresh": func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&refreshes, 1)
+					writeTestJSON(w, http.StatusOK, map[string]string{"access_token": fresh})
+				},
+				"/rest/test": func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&calls, 1)
+					if r.Header.Get("Authorization") != "Bearer "+fresh {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					if body, _ := io.ReadAll(r.Body); tc.method == http.MethodPost && string(body) != "payload" {
+						t.Errorf("retried request body = %q, want %q", body, "payload")
+					}
+				},
+			})
+
+			session := testHorizonSession(t, srv)
+			client := &http.Client{Transport: &authTransport{base: srv.Client().Transport, session: session}}
+
+			req, err := http.NewRequest(tc.method, srv.URL+"/rest/test", tc.body())
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("server called %d times, want %d", calls, tc.wantCalls)
+			}
+			if refreshes != tc.wantRefreshes {
+				t.Errorf("token refreshed %d times, want %d", refreshes, tc.wantRefreshes)
+			}
+		})
+	}
+}
+
+func TestHorizonSessionRefreshSkipsReplacedToken(t *testing.T) {
+	var refreshes int32
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/refresh": func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&refreshes, 1)
+			writeTestJSON(w, http.StatusOK, map[string]string{"access_token": testJWT(time.Now().Add(2 * time.Hour))})
+		},
+	})
+
+	session := testHorizonSession(t, srv)
+	current := session.accessToken
+
+	// another request already replaced the rejected token
+	token, err := session.refresh(context.Background(), "rejected")
+	if err != nil {
+		t.Fatalf("refresh failed: %s", err)
+	}
+	if token != current || refreshes != 0 {
+		t.Errorf("refresh replaced a token that was not rejected")
+	}
+
+	token, err = session.refresh(context.Background(), current)
+	if err != nil {
+		t.Fatalf("refresh failed: %s", err)
+	}
+	if token == current || refreshes != 1 {
+		t.Errorf("refresh did not replace the rejected token")
+	}
+}
+
+func TestLogout(t *testing.T) {
+	var logouts int32
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/logout": func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&logouts, 1)
+			if body, _ := io.ReadAll(r.Body); !strings.Contains(string(body), `"refresh"`) {
+				t.Errorf("logout body = %s, want the refresh token", body)
+			}
+		},
+	})
+
+	trackSession(testHorizonSession(t, srv))
+	trackSession(testHorizonSession(t, srv))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	Logout(ctx)
+	Logout(ctx)
+
+	if logouts != 2 {
+		t.Errorf("logged out %d sessions, want 2", logouts)
+	}
+}
+
+func TestLogoutDoesNotRetry(t *testing.T) {
+	var logouts int32
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/logout": func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&logouts, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+	})
+
+	retrying := gohorizon.NewAPIClient(newConfiguration(srv.Listener.Addr().String(), "test", &http.Client{
+		Transport: &retryTransport{base: srv.Client().Transport, maxRetries: 3, maxWait: time.Millisecond},
+	}))
+	session, err := newHorizonSession(context.Background(), retrying.AuthApi, "domain", "user", "password")
+	if err != nil {
+		t.Fatalf("error logging in: %s", err)
+	}
+	session.logoutAPI = gohorizon.NewAPIClient(newConfiguration(srv.Listener.Addr().String(), "test", srv.Client())).AuthApi
+	trackSession(session)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	Logout(ctx)
+
+	if logouts != 1 {
+		t.Errorf("sent %d logout requests, want a single attempt", logouts)
+	}
+}
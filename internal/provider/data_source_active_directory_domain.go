@@ -96,9 +96,9 @@ func dataSourceActiveDirectoryDomainRead(ctx context.Context, d *schema.Resource
 
 	dnsName := d.Get("dns_name").(string)
 
-	domains, _, err := client.ExternalApi.ListADDomainsV3(ctx).Execute()
+	domains, resp, err := client.ExternalApi.ListADDomainsV3(ctx).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, domain := range domains {
@@ -125,9 +125,9 @@ func dataSourceActiveDirectoryDomainUserRead(ctx context.Context, d *schema.Reso
 		listUserOrGroup.GroupOnly(strconv.FormatBool(g.(bool)))
 	}
 
-	entity, _, err := listUserOrGroup.Filter(filter).Execute()
+	entity, resp, err := listUserOrGroup.Filter(filter).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	switch len(entity) {
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceImageManagementStream() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source to find the ID of an Image Management Stream in the Horizon image catalog.",
+
+		ReadContext: dataSourceImageManagementStreamRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Image Management Stream name.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": {
+				Description: "Image Management Stream description.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"operating_system": {
+				Description: "Operating system of the Image Management Stream.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"publisher": {
+				Description: "Publisher of the Image Management Stream.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"source": {
+				Description: "Source of the Image Management Stream. MARKET_PLACE: Stream is from the market place. UPLOADED: Stream is uploaded. COPIED_FROM_STREAM: Stream is copied from another stream. COPIED_FROM_VERSION: Stream is copied from a version.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"status": {
+				Description: "Status of the Image Management Stream. AVAILABLE: Stream is available for desktop pools/farms to be created. DELETED: Stream is deleted. DISABLED: Stream is disabled. FAILED: Stream creation has failed. IN_PROGRESS: Stream creation is in progress. PARTIALLY_AVAILABLE: Version for this stream could not be created in one or more environments. PENDING: Stream is in pending state.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"usable": {
+				Description: "Indicates whether the Image Management Stream can be used for desktop pool or farm creation.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceImageManagementStreamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+
+	streams, resp, err := client.ConfigApi.ListIMStreams(ctx).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	for _, stream := range streams {
+		if stream.GetName() == name {
+			d.SetId(stream.GetId())
+			d.Set("description", stream.Description)
+			d.Set("operating_system", stream.OperatingSystem)
+			d.Set("publisher", stream.Publisher)
+			d.Set("source", stream.Source)
+			d.Set("status", stream.Status)
+			d.Set("usable", stream.Usable)
+
+			return nil
+		}
+	}
+
+	return diag.Errorf("could not find any Image Management Stream with name \"%s\"", name)
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceImageManagementStream(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/config/v1/im-streams": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "stream-1", "name": "win10", "operating_system": "WINDOWS_10", "source": "UPLOADED", "status": "AVAILABLE", "usable": true},
+				{"id": "stream-2", "name": "win11", "operating_system": "WINDOWS_11", "source": "MARKET_PLACE", "status": "PENDING", "usable": false},
+			})
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(srv) + testDataSourceImageManagementStream("win10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.horizon_image_management_stream.test", "id", "stream-1"),
+					resource.TestCheckResourceAttr("data.horizon_image_management_stream.test", "operating_system", "WINDOWS_10"),
+					resource.TestCheckResourceAttr("data.horizon_image_management_stream.test", "source", "UPLOADED"),
+					resource.TestCheckResourceAttr("data.horizon_image_management_stream.test", "status", "AVAILABLE"),
+					resource.TestCheckResourceAttr("data.horizon_image_management_stream.test", "usable", "true"),
+				),
+			},
+			{
+				Config:      testProviderConfig(srv) + testDataSourceImageManagementStream("win7"),
+				ExpectError: regexp.MustCompile(`could not find any Image Management Stream with name "win7"`),
+			},
+		},
+	})
+}
+
+func testDataSourceImageManagementStream(name string) string {
+	return fmt.Sprintf(`
+data "horizon_image_management_stream" "test" {
+  name = %q
+}
+`, name)
+}
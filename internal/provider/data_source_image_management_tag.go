@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceImageManagementTag() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source to find the ID of an Image Management Tag of an Image Management Stream.",
+
+		ReadContext: dataSourceImageManagementTagRead,
+
+		Schema: map[string]*schema.Schema{
+			"im_stream_id": {
+				Description: "ID of the Image Management Stream the tag belongs to.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Image Management Tag name.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": {
+				Description: "Image Management Tag description.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"im_version_id": {
+				Description: "ID of the Image Management Version the tag currently points to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceImageManagementTagRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	imStreamID := d.Get("im_stream_id").(string)
+	name := d.Get("name").(string)
+
+	tags, resp, err := client.ConfigApi.ListIMTags(ctx).ImStreamId(imStreamID).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	for _, tag := range tags {
+		if tag.GetName() == name {
+			d.SetId(tag.GetId())
+			d.Set("description", tag.Description)
+			d.Set("im_version_id", tag.ImVersionId)
+
+			return nil
+		}
+	}
+
+	return diag.Errorf("could not find any Image Management Tag with name \"%s\"", name)
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceImageManagementTag(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/config/v1/im-tags": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("im_stream_id"); got != "stream" {
+				t.Errorf("im_stream_id = %q, want %q", got, "stream")
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "tag-1", "name": "latest", "im_stream_id": "stream", "im_version_id": "version-2"},
+				{"id": "tag-2", "name": "stable", "im_stream_id": "stream", "im_version_id": "version-1", "description": "Production image"},
+			})
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(srv) + testDataSourceImageManagementTag("stable"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.horizon_image_management_tag.test", "id", "tag-2"),
+					resource.TestCheckResourceAttr("data.horizon_image_management_tag.test", "im_version_id", "version-1"),
+					resource.TestCheckResourceAttr("data.horizon_image_management_tag.test", "description", "Production image"),
+				),
+			},
+			{
+				Config:      testProviderConfig(srv) + testDataSourceImageManagementTag("beta"),
+				ExpectError: regexp.MustCompile(`could not find any Image Management Tag with name "beta"`),
+			},
+		},
+	})
+}
+
+func testDataSourceImageManagementTag(name string) string {
+	return fmt.Sprintf(`
+data "horizon_image_management_tag" "test" {
+  name         = %q
+  im_stream_id = "stream"
+}
+`, name)
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceImageManagementVersion() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source to find the ID of an Image Management Version of an Image Management Stream.",
+
+		ReadContext: dataSourceImageManagementVersionRead,
+
+		Schema: map[string]*schema.Schema{
+			"im_stream_id": {
+				Description: "ID of the Image Management Stream the version belongs to.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Image Management Version name.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": {
+				Description: "Image Management Version description.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"status": {
+				Description: "Status of the Image Management Version. AVAILABLE: Version is available for desktop pools/farms to be created. DEPLOYING_VM: Version is deploying VM on the selected pod. DEPLOYMENT_DONE: VM deployment is done for the selected pod. DELETED: Version has been deleted. DISABLED: Version has been disabled. FAILED: Version creation has failed. PARTIALLY_AVAILABLE: Some of the asset creation in some of the virtual centers have failed. PUBLISHING: Version is being published. REPLICATING: Copying the images across all virtual centers.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceImageManagementVersionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	imStreamID := d.Get("im_stream_id").(string)
+	name := d.Get("name").(string)
+
+	versions, resp, err := client.ConfigApi.ListIMVersions(ctx).ImStreamId(imStreamID).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	for _, version := range versions {
+		if version.GetName() == name {
+			d.SetId(version.GetId())
+			d.Set("description", version.Description)
+			d.Set("status", version.Status)
+
+			return nil
+		}
+	}
+
+	return diag.Errorf("could not find any Image Management Version with name \"%s\"", name)
+}
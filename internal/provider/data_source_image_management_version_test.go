@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceImageManagementVersion(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/config/v1/im-versions": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("im_stream_id"); got != "stream" {
+				t.Errorf("im_stream_id = %q, want %q", got, "stream")
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "version-1", "name": "1.0", "im_stream_id": "stream", "status": "AVAILABLE"},
+				{"id": "version-2", "name": "2.0", "im_stream_id": "stream", "status": "PUBLISHING"},
+			})
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(srv) + testDataSourceImageManagementVersion("2.0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.horizon_image_management_version.test", "id", "version-2"),
+					resource.TestCheckResourceAttr("data.horizon_image_management_version.test", "status", "PUBLISHING"),
+				),
+			},
+			{
+				Config:      testProviderConfig(srv) + testDataSourceImageManagementVersion("3.0"),
+				ExpectError: regexp.MustCompile(`could not find any Image Management Version with name "3.0"`),
+			},
+		},
+	})
+}
+
+func testDataSourceImageManagementVersion(name string) string {
+	return fmt.Sprintf(`
+data "horizon_image_management_version" "test" {
+  name         = %q
+  im_stream_id = "stream"
+}
+`, name)
+}
@@ -34,9 +34,9 @@ func dataSourceInstantCloneDomainAccountRead(ctx context.Context, d *schema.Reso
 	username := d.Get("username").(string)
 	domainSID := d.Get("ad_domain_id").(string)
 
-	accounts, _, err := client.ConfigApi.ListICDomainAccounts(ctx).Execute()
+	accounts, resp, err := client.ConfigApi.ListICDomainAccounts(ctx).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, account := range accounts {
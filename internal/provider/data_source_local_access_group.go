@@ -38,9 +38,9 @@ func dataSourceLocalAccessGroupRead(ctx context.Context, d *schema.ResourceData,
 
 	name := d.Get("name").(string)
 
-	groups, _, err := client.ConfigApi.ListLocalAccessGroups(ctx).Execute()
+	groups, resp, err := client.ConfigApi.ListLocalAccessGroups(ctx).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, group := range groups {
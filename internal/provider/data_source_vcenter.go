@@ -103,9 +103,9 @@ func dataSourcevCenterRead(ctx context.Context, d *schema.ResourceData, meta int
 
 	serverName := d.Get("server_name").(string)
 
-	vCenters, _, err := client.ConfigApi.ListVCInfoV2(ctx).Execute()
+	vCenters, resp, err := client.ConfigApi.ListVCInfoV2(ctx).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, vCenter := range vCenters {
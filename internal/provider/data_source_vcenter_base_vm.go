@@ -76,9 +76,9 @@ func dataSourcevCenterBaseVMRead(ctx context.Context, d *schema.ResourceData, me
 		listBaseVMs.FilterIncompatibleVms(filterIncompat.(bool))
 	}
 
-	baseVMs, _, err := listBaseVMs.VcenterId(vCenterID).Execute()
+	baseVMs, resp, err := listBaseVMs.VcenterId(vCenterID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, baseVM := range baseVMs {
@@ -82,6 +82,30 @@ func dataSourcevCenterBaseVMSnapshot() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"network_interface_cards": {
+				Description: "Network interface cards of the VM snapshot. The IDs can be used to configure the nics of instant clone desktop pools.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "Network interface card ID.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"mac_address": {
+							Description: "Network interface card MAC address.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": {
+							Description: "Network interface card name.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
 			"renderer3d": {
 				Description: "Indicate how the virtual video device for the VM snapshot renders 3D graphics. Will be set only if VM snapshot supports 3D functions. MANAGE_BY_VSPHERE_CLIENT: 3D rendering managed by vSphere Client. AUTOMATIC: 3D rendering is automatic. SOFTWARE: 3D rendering is software dependent. The software renderer is supported (at minimum) on virtual hardware version 8 in a vSphere 5.0 environment. HARDWARE: 3D rendering is hardware dependent. The hardware-based renderer is supported (at minimum) on virtual hardware version 9 in a vSphere 5.1 environment. DISABLED: 3D rendering is disabled.",
 				Type:        schema.TypeString,
@@ -108,9 +132,9 @@ func dataSourcevCenterBaseVMSnapshotRead(ctx context.Context, d *schema.Resource
 	path := d.Get("path").(string)
 	vCenterID := d.Get("vcenter_id").(string)
 
-	snapshots, _, err := client.ExternalApi.ListBaseSnapshots(ctx).BaseVmId(bvmID).VcenterId(vCenterID).Execute()
+	snapshots, resp, err := client.ExternalApi.ListBaseSnapshots(ctx).BaseVmId(bvmID).VcenterId(vCenterID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, snapshot := range snapshots {
@@ -130,6 +154,21 @@ func dataSourcevCenterBaseVMSnapshotRead(ctx context.Context, d *schema.Resource
 			d.Set("total_video_memory_mb", snapshot.TotalVideoMemoryMb)
 			d.Set("vgpu_type", snapshot.VgpuType)
 
+			nics, resp, err := client.ExternalApi.ListNetworkInterfaceCards(ctx).BaseSnapshotId(*snapshot.Id).BaseVmId(bvmID).VcenterId(vCenterID).Execute()
+			if err != nil {
+				return returnResponseErr(resp, err)
+			}
+
+			networkInterfaceCards := []interface{}{}
+			for _, nic := range nics {
+				networkInterfaceCards = append(networkInterfaceCards, map[string]interface{}{
+					"id":          nic.GetId(),
+					"mac_address": nic.GetMacAddress(),
+					"name":        nic.GetName(),
+				})
+			}
+			d.Set("network_interface_cards", networkInterfaceCards)
+
 			return nil
 		}
 	}
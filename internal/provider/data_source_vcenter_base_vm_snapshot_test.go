@@ -1,32 +1,68 @@
 package provider
 
 import (
+	"fmt"
+	"net/http"
 	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
-func TestAccDataSourcevCenterBaseVMSnapshots(t *testing.T) {
-	t.Skip("data source not yet implemented, remove this once you add your own code")
+func TestDataSourcevCenterBaseVMSnapshot(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/external/v1/base-snapshots": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("base_vm_id"); got != "base-vm" {
+				t.Errorf("base_vm_id = %q, want %q", got, "base-vm")
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "snapshot-1", "name": "initial", "path": "/initial"},
+				{"id": "snapshot-2", "name": "patched", "path": "/initial/patched", "memory_mb": 8192},
+			})
+		},
+		"/rest/external/v1/network-interface-cards": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("base_snapshot_id"); got != "snapshot-2" {
+				t.Errorf("base_snapshot_id = %q, want %q", got, "snapshot-2")
+			}
+			if got := r.URL.Query().Get("vcenter_id"); got != "vcenter" {
+				t.Errorf("vcenter_id = %q, want %q", got, "vcenter")
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "nic-1", "name": "Network adapter 1", "mac_address": "00:50:56:00:00:01"},
+				{"id": "nic-2", "name": "Network adapter 2", "mac_address": "00:50:56:00:00:02"},
+			})
+		},
+	})
 
 	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: providerFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccDataSourcevCenterBaseVMSnapshots,
+				Config: testProviderConfig(srv) + testDataSourcevCenterBaseVMSnapshot("/initial/patched"),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestMatchResourceAttr(
-						"data.scaffolding_data_source.foo", "sample_attribute", regexp.MustCompile("^ba")),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_base_vm_snapshot.test", "id", "snapshot-2"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_base_vm_snapshot.test", "name", "patched"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_base_vm_snapshot.test", "memory_mb", "8192"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_base_vm_snapshot.test", "network_interface_cards.#", "2"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_base_vm_snapshot.test", "network_interface_cards.0.id", "nic-1"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_base_vm_snapshot.test", "network_interface_cards.1.name", "Network adapter 2"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_base_vm_snapshot.test", "network_interface_cards.1.mac_address", "00:50:56:00:00:02"),
 				),
 			},
+			{
+				Config:      testProviderConfig(srv) + testDataSourcevCenterBaseVMSnapshot("/missing"),
+				ExpectError: regexp.MustCompile(`could not find any Base VM Snapshot with path "/missing"`),
+			},
 		},
 	})
 }
 
-const testAccDataSourcevCenterBaseVMSnapshots = `
-data "scaffolding_data_source" "foo" {
-  sample_attribute = "bar"
+func testDataSourcevCenterBaseVMSnapshot(path string) string {
+	return fmt.Sprintf(`
+data "horizon_vcenter_base_vm_snapshot" "test" {
+  base_vm_id = "base-vm"
+  path       = %q
+  vcenter_id = "vcenter"
+}
+`, path)
 }
-`
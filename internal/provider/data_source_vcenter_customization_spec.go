@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcevCenterCustomizationSpec() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for Sysprep customization specification information. Customization specifications are used to customize full clone desktop pools with Sysprep.",
+
+		ReadContext: dataSourcevCenterCustomizationSpecRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Customization specification name.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"vcenter_id": {
+				Description: "Virtual Center ID",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": {
+				Description: "Customization specification description.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"guest_os": {
+				Description: "Guest operating system type of the customization specification.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"incompatible_reasons": {
+				Description: "Reasons that may preclude this customization specification from being used in desktop pool creation.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourcevCenterCustomizationSpecRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	vCenterID := d.Get("vcenter_id").(string)
+
+	custSpecs, resp, err := client.ExternalApi.ListCustomizationSpecs(ctx).VcenterId(vCenterID).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	for _, custSpec := range custSpecs {
+		if custSpec.GetName() == name {
+			d.SetId(custSpec.GetId())
+			d.Set("description", custSpec.Description)
+			d.Set("guest_os", custSpec.GuestOs)
+			d.Set("incompatible_reasons", custSpec.IncompatibleReasons)
+
+			return nil
+		}
+	}
+
+	return diag.Errorf("could not find any customization specification with name \"%s\"", name)
+}
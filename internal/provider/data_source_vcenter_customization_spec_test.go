@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourcevCenterCustomizationSpec(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/external/v1/customization-specifications": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("vcenter_id"); got != "vcenter" {
+				t.Errorf("vcenter_id = %q, want %q", got, "vcenter")
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "spec-1", "name": "Linux", "guest_os": "LINUX"},
+				{"id": "spec-2", "name": "Windows", "guest_os": "WINDOWS", "description": "Join the domain"},
+			})
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(srv) + testDataSourcevCenterCustomizationSpec("Windows"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.horizon_vcenter_customization_spec.test", "id", "spec-2"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_customization_spec.test", "guest_os", "WINDOWS"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_customization_spec.test", "description", "Join the domain"),
+				),
+			},
+			{
+				Config:      testProviderConfig(srv) + testDataSourcevCenterCustomizationSpec("macOS"),
+				ExpectError: regexp.MustCompile(`could not find any customization specification with name "macOS"`),
+			},
+		},
+	})
+}
+
+func testDataSourcevCenterCustomizationSpec(name string) string {
+	return fmt.Sprintf(`
+data "horizon_vcenter_customization_spec" "test" {
+  name       = %q
+  vcenter_id = "vcenter"
+}
+`, name)
+}
@@ -39,9 +39,9 @@ func dataSourcevCenterDatacenterRead(ctx context.Context, d *schema.ResourceData
 	name := d.Get("name").(string)
 	vCenterID := d.Get("vcenter_id").(string)
 
-	datacenters, _, err := client.ExternalApi.ListDatacenters(ctx).VcenterId(vCenterID).Execute()
+	datacenters, resp, err := client.ExternalApi.ListDatacenters(ctx).VcenterId(vCenterID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, datacenter := range datacenters {
@@ -93,9 +93,9 @@ func dataSourcevCenterDatastoreRead(ctx context.Context, d *schema.ResourceData,
 	name := d.Get("name").(string)
 	vCenterID := d.Get("vcenter_id").(string)
 
-	datastores, _, err := client.ExternalApi.Listdatastores(ctx).HostOrClusterId(hcID).VcenterId(vCenterID).Execute()
+	datastores, resp, err := client.ExternalApi.Listdatastores(ctx).HostOrClusterId(hcID).VcenterId(vCenterID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, datastore := range datastores {
@@ -65,9 +65,9 @@ func dataSourcevCenterHostOrClusterRead(ctx context.Context, d *schema.ResourceD
 	vCenterID := d.Get("vcenter_id").(string)
 	cluster := d.Get("cluster").(bool)
 
-	hostsOrClusters, _, err := client.ExternalApi.ListHostsOrClusters(ctx).DatacenterId(datacenterID).VcenterId(vCenterID).Execute()
+	hostsOrClusters, resp, err := client.ExternalApi.ListHostsOrClusters(ctx).DatacenterId(datacenterID).VcenterId(vCenterID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, hostOrCluster := range hostsOrClusters {
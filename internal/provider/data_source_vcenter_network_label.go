@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcevCenterNetworkLabel() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source to find the ID of a vCenter Network Label.",
+
+		ReadContext: dataSourcevCenterNetworkLabelRead,
+
+		Schema: map[string]*schema.Schema{
+			"host_or_cluster_id": {
+				Description: "Host or Cluster ID",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Network label name.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"vcenter_id": {
+				Description: "Virtual Center ID",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"available_ports": {
+				Description: "Available ports in network label.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"incompatible_reasons": {
+				Description: "Reasons that may preclude this Network Label from being used in desktop pool/farm configuration.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"label_type": {
+				Description: "Network label type. EARLY_BINDING: A free Distributed Virtual Port will be selected and assigned to a Virtual Machine when the Virtual Machine is reconfigured to connect to the portgroup. Instant clones desktop pools/farms only support port group type of early binding. EPHEMERAL: A Distributed Virtual Port will be created and assigned to a Virtual Machine when the Virtual Machine is powered on, and will be deleted when the Virtual Machine is powered off. LATE_BINDING: A free DistributedVirtualPort will be selected and assigned to a Virtual Machine when the Virtual Machine is powered on.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"max_ports": {
+				Description: "The total number of ports present.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"switch_type": {
+				Description: "Network label switch type. STANDARD_SWITCH: Standard Switch. DISTRIBUTED_VIRTUAL_SWITCH: Distributed Virtual Switch. NSX_NETWORK_SWITCH: NSX network Switch.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourcevCenterNetworkLabelRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	hcID := d.Get("host_or_cluster_id").(string)
+	name := d.Get("name").(string)
+	vCenterID := d.Get("vcenter_id").(string)
+
+	networkLabels, resp, err := client.ExternalApi.ListNetworkLabels(ctx).HostOrClusterId(hcID).VcenterId(vCenterID).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	for _, networkLabel := range networkLabels {
+		if networkLabel.GetName() == name {
+			d.SetId(networkLabel.GetId())
+			d.Set("available_ports", networkLabel.AvailablePorts)
+			d.Set("incompatible_reasons", networkLabel.IncompatibleReasons)
+			d.Set("label_type", networkLabel.LabelType)
+			d.Set("max_ports", networkLabel.MaxPorts)
+			d.Set("switch_type", networkLabel.SwitchType)
+
+			return nil
+		}
+	}
+
+	return diag.Errorf("could not find any network label with name \"%s\"", name)
+}
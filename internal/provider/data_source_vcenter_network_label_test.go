@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourcevCenterNetworkLabel(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/external/v1/network-labels": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("vcenter_id"); got != "vcenter" {
+				t.Errorf("vcenter_id = %q, want %q", got, "vcenter")
+			}
+			if got := r.URL.Query().Get("host_or_cluster_id"); got != "cluster" {
+				t.Errorf("host_or_cluster_id = %q, want %q", got, "cluster")
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "unnamed"},
+				{"id": "network-1", "name": "VM Network", "label_type": "STANDARD_NETWORK", "max_ports": 0},
+				{"id": "network-2", "name": "VDI", "label_type": "DISTRIBUTED_VIRTUAL_PORT_GROUP", "max_ports": 128, "available_ports": 100},
+			})
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(srv) + testDataSourcevCenterNetworkLabel("VDI"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.horizon_vcenter_network_label.test", "id", "network-2"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_network_label.test", "label_type", "DISTRIBUTED_VIRTUAL_PORT_GROUP"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_network_label.test", "max_ports", "128"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_network_label.test", "available_ports", "100"),
+				),
+			},
+			{
+				Config:      testProviderConfig(srv) + testDataSourcevCenterNetworkLabel("Storage"),
+				ExpectError: regexp.MustCompile(`could not find any network label with name "Storage"`),
+			},
+		},
+	})
+}
+
+func testDataSourcevCenterNetworkLabel(name string) string {
+	return fmt.Sprintf(`
+data "horizon_vcenter_network_label" "test" {
+  name               = %q
+  vcenter_id         = "vcenter"
+  host_or_cluster_id = "cluster"
+}
+`, name)
+}
@@ -50,9 +50,9 @@ func dataSourcevCenterResourcePoolRead(ctx context.Context, d *schema.ResourceDa
 	name := d.Get("name").(string)
 	vCenterID := d.Get("vcenter_id").(string)
 
-	resourcePools, _, err := client.ExternalApi.ListResourcePools(ctx).HostOrClusterId(hcID).VcenterId(vCenterID).Execute()
+	resourcePools, resp, err := client.ExternalApi.ListResourcePools(ctx).HostOrClusterId(hcID).VcenterId(vCenterID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, resourcePool := range resourcePools {
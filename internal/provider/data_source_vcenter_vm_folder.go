@@ -58,9 +58,9 @@ func dataSourcevCenterVMFolderRead(ctx context.Context, d *schema.ResourceData,
 	path := d.Get("path").(string)
 	vCenterID := d.Get("vcenter_id").(string)
 
-	vmFolders, _, err := client.ExternalApi.ListVMFolders(ctx).DatacenterId(dcID).VcenterId(vCenterID).Execute()
+	vmFolders, resp, err := client.ExternalApi.ListVMFolders(ctx).DatacenterId(dcID).VcenterId(vCenterID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	for _, vmFolder := range vmFolders {
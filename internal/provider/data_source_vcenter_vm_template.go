@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcevCenterVMTemplate() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for VM template information. VM templates are used as the source of full clone desktop pools.",
+
+		ReadContext: dataSourcevCenterVMTemplateRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "VM template name.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"vcenter_id": {
+				Description: "Virtual Center ID",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"datacenter_id": {
+				Description: "Datacenter ID",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"incompatible_reasons": {
+				Description: "Reasons that may preclude this VM template from being used in full clone desktop pool creation.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"operating_system": {
+				Description: "Operating system of the VM template.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"operating_system_display_name": {
+				Description: "Operating system display name from Virtual Center.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"path": {
+				Description: "VM template path.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourcevCenterVMTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	vCenterID := d.Get("vcenter_id").(string)
+	listVMTemplates := client.ExternalApi.ListVMTemplates(ctx)
+
+	if dcID, ok := d.GetOk("datacenter_id"); ok {
+		listVMTemplates = listVMTemplates.DatacenterId(dcID.(string))
+	}
+
+	vmTemplates, resp, err := listVMTemplates.VcenterId(vCenterID).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	for _, vmTemplate := range vmTemplates {
+		if vmTemplate.GetName() == name {
+			d.SetId(vmTemplate.GetId())
+			d.Set("datacenter_id", vmTemplate.DatacenterId)
+			d.Set("incompatible_reasons", vmTemplate.IncompatibleReasons)
+			d.Set("operating_system", vmTemplate.OperatingSystem)
+			d.Set("operating_system_display_name", vmTemplate.OperatingSystemDisplayName)
+			d.Set("path", vmTemplate.Path)
+
+			return nil
+		}
+	}
+
+	return diag.Errorf("could not find any VM template with name \"%s\"", name)
+}
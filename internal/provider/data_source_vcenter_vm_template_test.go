@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourcevCenterVMTemplate(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/external/v1/vm-templates": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("vcenter_id"); got != "vcenter" {
+				t.Errorf("vcenter_id = %q, want %q", got, "vcenter")
+			}
+			if got := r.URL.Query().Get("datacenter_id"); got != "datacenter" {
+				t.Errorf("datacenter_id = %q, want %q", got, "datacenter")
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				{"id": "template-1", "name": "win10", "datacenter_id": "datacenter", "path": "/dc/vm/win10", "operating_system": "WINDOWS_10"},
+				{"id": "template-2", "name": "win11", "datacenter_id": "datacenter", "path": "/dc/vm/win11", "operating_system": "WINDOWS_11"},
+			})
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(srv) + testDataSourcevCenterVMTemplate("win11"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.horizon_vcenter_vm_template.test", "id", "template-2"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_vm_template.test", "path", "/dc/vm/win11"),
+					resource.TestCheckResourceAttr("data.horizon_vcenter_vm_template.test", "operating_system", "WINDOWS_11"),
+				),
+			},
+			{
+				Config:      testProviderConfig(srv) + testDataSourcevCenterVMTemplate("win7"),
+				ExpectError: regexp.MustCompile(`could not find any VM template with name "win7"`),
+			},
+		},
+	})
+}
+
+func testDataSourcevCenterVMTemplate(name string) string {
+	return fmt.Sprintf(`
+data "horizon_vcenter_vm_template" "test" {
+  name          = %q
+  vcenter_id    = "vcenter"
+  datacenter_id = "datacenter"
+}
+`, name)
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/umich-vci/gohorizon"
+)
+
+// horizonAPIError is the error payload returned by the Horizon REST API.
+type horizonAPIError struct {
+	Status        string                  `json:"status"`
+	ErrorKey      string                  `json:"error_key"`
+	ErrorMessage  string                  `json:"error_message"`
+	ErrorMessages []string                `json:"error_messages"`
+	Errors        []horizonAPIErrorDetail `json:"errors"`
+}
+
+// horizonAPIErrorDetail is a single error of a Horizon REST API error payload.
+type horizonAPIErrorDetail struct {
+	ErrorKey     string `json:"error_key"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// errorFieldRegexp matches the API field that validation errors start with, such as
+// "provisioning_settings.parent_vm_id must not be null" or "display_name: size must be between 1 and 256".
+var errorFieldRegexp = regexp.MustCompile(`^([a-z][a-z0-9]*(?:[_.][a-z0-9]+)+:?|[a-z][a-z0-9]*:)\s`)
+
+// returnResponseErr turns an error returned by the Horizon REST API into diagnostics, with one
+// diagnostic per error of the payload. resp may be nil when the request never got a response.
+func returnResponseErr(resp *http.Response, err error) diag.Diagnostics {
+	body := responseErrBody(resp, err)
+	if len(body) == 0 {
+		switch {
+		case err != nil:
+			return diag.FromErr(err)
+		case resp != nil:
+			return diag.Errorf("unexpected response from Horizon: %s", resp.Status)
+		default:
+			return diag.Errorf("no response from Horizon")
+		}
+	}
+
+	summary := "Horizon API error"
+	if err != nil {
+		summary = fmt.Sprintf("Horizon API error: %s", err)
+	} else if resp != nil {
+		summary = fmt.Sprintf("Horizon API error: %s", resp.Status)
+	}
+
+	var apiErr horizonAPIError
+	if json.Unmarshal(body, &apiErr) != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   strings.TrimSpace(string(body)),
+		}}
+	}
+
+	details := apiErr.Errors
+	if apiErr.ErrorKey != "" || apiErr.ErrorMessage != "" {
+		details = append(details, horizonAPIErrorDetail{ErrorKey: apiErr.ErrorKey, ErrorMessage: apiErr.ErrorMessage})
+	}
+
+	var diags diag.Diagnostics
+	for _, detail := range details {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       summary,
+			Detail:        errorDetailText(detail),
+			AttributePath: errorAttributePath(detail.ErrorMessage),
+		})
+	}
+
+	// the error messages usually repeat the errors above, so they are only used if there are none
+	if len(diags) == 0 {
+		for _, message := range apiErr.ErrorMessages {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       summary,
+				Detail:        message,
+				AttributePath: errorAttributePath(message),
+			})
+		}
+	}
+
+	if len(diags) == 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   strings.TrimSpace(string(body)),
+		})
+	}
+
+	return diags
+}
+
+// responseErrBody returns the body of a failed response. The generated client has already read it,
+// so it is taken from the error if possible.
+func responseErrBody(resp *http.Response, err error) []byte {
+	var apiErr gohorizon.GenericOpenAPIError
+	if errors.As(err, &apiErr) && len(apiErr.Body()) > 0 {
+		return apiErr.Body()
+	}
+
+	if resp == nil || resp.Body == nil {
+		return nil
+	}
+
+	body, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil
+	}
+
+	return body
+}
+
+func errorDetailText(detail horizonAPIErrorDetail) string {
+	switch {
+	case detail.ErrorMessage == "":
+		return detail.ErrorKey
+	case detail.ErrorKey == "":
+		return detail.ErrorMessage
+	default:
+		return fmt.Sprintf("%s (%s)", detail.ErrorMessage, detail.ErrorKey)
+	}
+}
+
+// errorAttributePath returns the path of the attribute named at the start of an error message, or nil.
+// Attributes are named after the API fields and nested objects are blocks holding a single element.
+func errorAttributePath(message string) cty.Path {
+	match := errorFieldRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return nil
+	}
+
+	var path cty.Path
+	for i, name := range strings.Split(strings.TrimSuffix(match[1], ":"), ".") {
+		if i > 0 {
+			path = path.IndexInt(0)
+		}
+		path = path.GetAttr(name)
+	}
+
+	return path
+}
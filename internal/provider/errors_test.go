@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/umich-vci/gohorizon"
+)
+
+func TestReturnResponseErr(t *testing.T) {
+	response := func(status int, body string) *http.Response {
+		return &http.Response{
+			Status:     http.StatusText(status),
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	cases := []struct {
+		name        string
+		resp        *http.Response
+		err         error
+		wantSummary []string
+		wantDetail  []string
+		wantPath    []cty.Path
+	}{
+		{
+			name:        "network error without response",
+			err:         errors.New("dial tcp: connection refused"),
+			wantSummary: []string{"dial tcp: connection refused"},
+			wantDetail:  []string{""},
+			wantPath:    []cty.Path{nil},
+		},
+		{
+			name:        "no response and no error",
+			wantSummary: []string{"no response from Horizon"},
+			wantDetail:  []string{""},
+			wantPath:    []cty.Path{nil},
+		},
+		{
+			name:        "empty body",
+			resp:        response(http.StatusBadGateway, ""),
+			wantSummary: []string{"unexpected response from Horizon: Bad Gateway"},
+			wantDetail:  []string{""},
+			wantPath:    []cty.Path{nil},
+		},
+		{
+			name: "errors with field",
+			resp: response(http.StatusBadRequest, `{
+				"status": "BAD_REQUEST",
+				"error_messages": ["Invalid input"],
+				"errors": [
+					{"error_key": "invalid.input", "error_message": "provisioning_settings.parent_vm_id must not be null"},
+					{"error_key": "duplicate.name", "error_message": "A desktop pool with this name already exists"}
+				]
+			}`),
+			err:         errors.New("400 Bad Request"),
+			wantSummary: []string{"Horizon API error: 400 Bad Request", "Horizon API error: 400 Bad Request"},
+			wantDetail: []string{
+				"provisioning_settings.parent_vm_id must not be null (invalid.input)",
+				"A desktop pool with this name already exists (duplicate.name)",
+			},
+			wantPath: []cty.Path{
+				cty.GetAttrPath("provisioning_settings").IndexInt(0).GetAttr("parent_vm_id"),
+				nil,
+			},
+		},
+		{
+			name:        "single error",
+			resp:        response(http.StatusNotFound, `{"error_key": "not.found", "error_message": "Desktop pool not found"}`),
+			err:         errors.New("404 Not Found"),
+			wantSummary: []string{"Horizon API error: 404 Not Found"},
+			wantDetail:  []string{"Desktop pool not found (not.found)"},
+			wantPath:    []cty.Path{nil},
+		},
+		{
+			name:        "error messages only",
+			resp:        response(http.StatusConflict, `{"status": "CONFLICT", "error_messages": ["name: already in use", "Try again later"]}`),
+			err:         errors.New("409 Conflict"),
+			wantSummary: []string{"Horizon API error: 409 Conflict", "Horizon API error: 409 Conflict"},
+			wantDetail:  []string{"name: already in use", "Try again later"},
+			wantPath:    []cty.Path{cty.GetAttrPath("name"), nil},
+		},
+		{
+			name:        "JSON without errors",
+			resp:        response(http.StatusInternalServerError, `{"status": "INTERNAL_SERVER_ERROR"}`),
+			err:         errors.New("500 Internal Server Error"),
+			wantSummary: []string{"Horizon API error: 500 Internal Server Error"},
+			wantDetail:  []string{`{"status": "INTERNAL_SERVER_ERROR"}`},
+			wantPath:    []cty.Path{nil},
+		},
+		{
+			name:        "body that is not JSON",
+			resp:        response(http.StatusServiceUnavailable, "<html>Service Unavailable</html>\n"),
+			err:         errors.New("503 Service Unavailable"),
+			wantSummary: []string{"Horizon API error: 503 Service Unavailable"},
+			wantDetail:  []string{"<html>Service Unavailable</html>"},
+			wantPath:    []cty.Path{nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkDiagnostics(t, returnResponseErr(tc.resp, tc.err), tc.wantSummary, tc.wantDetail, tc.wantPath)
+		})
+	}
+}
+
+func TestReturnResponseErrFromClient(t *testing.T) {
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"status": "BAD_REQUEST",
+				"errors": []map[string]string{{"error_key": "invalid.input", "error_message": "display_name: size must be between 1 and 256"}},
+			})
+		},
+	})
+
+	client := gohorizon.NewAPIClient(newConfiguration(srv.Listener.Addr().String(), "test", srv.Client()))
+	_, resp, err := client.InventoryApi.GetDesktopPoolV5(context.Background(), "pool").Execute()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	checkDiagnostics(t, returnResponseErr(resp, err),
+		[]string{"Horizon API error: 400 Bad Request"},
+		[]string{"display_name: size must be between 1 and 256 (invalid.input)"},
+		[]cty.Path{cty.GetAttrPath("display_name")},
+	)
+}
+
+func TestErrorAttributePath(t *testing.T) {
+	cases := []struct {
+		message string
+		want    cty.Path
+	}{
+		{"provisioning_settings.parent_vm_id must not be null", cty.GetAttrPath("provisioning_settings").IndexInt(0).GetAttr("parent_vm_id")},
+		{"display_name: size must be between 1 and 256", cty.GetAttrPath("display_name")},
+		{"name: must not be blank", cty.GetAttrPath("name")},
+		{"access_group_id is invalid", cty.GetAttrPath("access_group_id")},
+		{"session_settings.timeout_after_disconnect: must be positive", cty.GetAttrPath("session_settings").IndexInt(0).GetAttr("timeout_after_disconnect")},
+		{"name must not be blank", nil},
+		{"Desktop pool not found", nil},
+		{"", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.message, func(t *testing.T) {
+			if got := errorAttributePath(tc.message); !got.Equals(tc.want) {
+				t.Errorf("errorAttributePath(%q) = %#v, want %#v", tc.message, got, tc.want)
+			}
+		})
+	}
+}
+
+func checkDiagnostics(t *testing.T, diags diag.Diagnostics, wantSummary, wantDetail []string, wantPath []cty.Path) {
+	t.Helper()
+
+	if len(diags) != len(wantSummary) {
+		t.Fatalf("got %d diagnostics, want %d: %#v", len(diags), len(wantSummary), diags)
+	}
+
+	for i, d := range diags {
+		if d.Severity != diag.Error {
+			t.Errorf("diagnostic %d is not an error", i)
+		}
+		if d.Summary != wantSummary[i] {
+			t.Errorf("diagnostic %d summary = %q, want %q", i, d.Summary, wantSummary[i])
+		}
+		if d.Detail != wantDetail[i] {
+			t.Errorf("diagnostic %d detail = %q, want %q", i, d.Detail, wantDetail[i])
+		}
+		if !reflect.DeepEqual(d.AttributePath, wantPath[i]) && !d.AttributePath.Equals(wantPath[i]) {
+			t.Errorf("diagnostic %d path = %#v, want %#v", i, d.AttributePath, wantPath[i])
+		}
+	}
+}
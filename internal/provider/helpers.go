@@ -1,21 +1,214 @@
 package provider
 
 import (
-	"io"
-	"net/http"
+	"context"
+	"fmt"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/umich-vci/gohorizon"
 )
 
-func returnResponseErr(resp *http.Response, err error) diag.Diagnostics {
-	var diags diag.Diagnostics
-	diags = append(diags, diag.FromErr(err)...)
+// listPageSize is the page size used when walking paginated list APIs.
+const listPageSize = 1000
+
+// blockConfigured reports whether the named block is present in the raw configuration.
+func blockConfigured(config cty.Value, name string) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+
+	block := config.GetAttr(name)
+	if block.IsNull() || !block.IsKnown() {
+		return false
+	}
+
+	return block.LengthInt() > 0
+}
+
+// configBool reports whether the named attribute is set to true in the raw configuration.
+func configBool(config cty.Value, name string) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+
+	attr := config.GetAttr(name)
+	if attr.IsNull() || !attr.IsKnown() {
+		return false
+	}
+
+	return attr.True()
+}
+
+// diffValueSet reports whether a string attribute of a planned resource is set. Values that are not
+// known until apply, such as IDs from other resources, are treated as set.
+func diffValueSet(d *schema.ResourceDiff, key string) bool {
+	if !d.NewValueKnown(key) {
+		return true
+	}
+
+	return d.Get(key).(string) != ""
+}
+
+// findDesktopPoolIDByName looks up the ID of a desktop pool by name.
+// Desktop pool names are unique across the environment.
+func findDesktopPoolIDByName(ctx context.Context, client *gohorizon.APIClient, name string) (string, diag.Diagnostics) {
+	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"name\",\"value\":\"%s\"}", name)
+	pools, resp, err := client.InventoryApi.ListDesktopPoolsV5(ctx).Filter(filter).Execute()
+	if err != nil {
+		return "", returnResponseErr(resp, err)
+	}
 
-	b, err := io.ReadAll(resp.Body)
+	switch len(pools) {
+	case 0:
+		return "", diag.Errorf("could not find ID of pool that was created")
+	case 1:
+		return *pools[0].Id, nil
+	default:
+		return "", diag.Errorf("Multiple pools found with same name - should not be possible")
+	}
+}
+
+// findFarmIDByName looks up the ID of a farm by name.
+// Farm names are unique across the environment.
+func findFarmIDByName(ctx context.Context, client *gohorizon.APIClient, name string) (string, diag.Diagnostics) {
+	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"name\",\"value\":\"%s\"}", name)
+	farms, resp, err := client.InventoryApi.ListFarmsV3(ctx).Filter(filter).Execute()
 	if err != nil {
-		diags = append(diags, diag.FromErr(err)...)
+		return "", returnResponseErr(resp, err)
+	}
+
+	switch len(farms) {
+	case 0:
+		return "", diag.Errorf("could not find ID of farm that was created")
+	case 1:
+		return *farms[0].Id, nil
+	default:
+		return "", diag.Errorf("Multiple farms found with same name - should not be possible")
+	}
+}
+
+// listDesktopPoolMachines returns all of the machines that belong to a desktop pool.
+func listDesktopPoolMachines(ctx context.Context, client *gohorizon.APIClient, poolID string) ([]gohorizon.MachineInfo, diag.Diagnostics) {
+	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"desktop_pool_id\",\"value\":\"%s\"}", poolID)
+
+	machines := []gohorizon.MachineInfo{}
+	for page := int32(1); ; page++ {
+		pageMachines, resp, err := client.InventoryApi.ListMachines(ctx).Filter(filter).Page(page).Size(listPageSize).Execute()
+		if err != nil {
+			return nil, returnResponseErr(resp, err)
+		}
+
+		machines = append(machines, pageMachines...)
+		if len(pageMachines) < listPageSize {
+			return machines, nil
+		}
+	}
+}
+
+// bulkItemResponseErrors converts any failed items of a bulk API response into diagnostics.
+func bulkItemResponseErrors(items []gohorizon.BulkItemResponseInfo) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, item := range items {
+		if item.GetStatusCode() < 400 {
+			continue
+		}
+
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("operation failed for %s with status %d", item.GetId(), item.GetStatusCode()),
+			Detail:   strings.Join(item.GetErrorMessages(), "\n"),
+		})
+	}
+
+	return diags
+}
+
+// bulkEntitlementResponseErrors converts any failed items of a bulk entitlement API response into
+// diagnostics, with one diagnostic per failed user or group if the response has the details.
+func bulkEntitlementResponseErrors(items []gohorizon.BulkEntitlementResponseInfo) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, item := range items {
+		failedDetails := false
+		for _, detail := range item.GetDetails() {
+			if detail.GetStatusCode() < 400 {
+				continue
+			}
+
+			failedDetails = true
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("entitlement of %s to %s failed with status %d", detail.GetAdUserOrGroupId(), item.GetId(), detail.GetStatusCode()),
+				Detail:   strings.Join(detail.GetErrorMessages(), "\n"),
+			})
+		}
+
+		if item.GetStatusCode() < 400 || failedDetails {
+			continue
+		}
+
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("entitlement operation failed for %s with status %d", item.GetId(), item.GetStatusCode()),
+			Detail:   strings.Join(item.GetErrorMessages(), "\n"),
+		})
 	}
 
-	diags = append(diags, diag.Errorf(string(b))...)
 	return diags
 }
+
+// desktopPoolMachineErrors returns a diagnostic for each machine that failed to provision.
+func desktopPoolMachineErrors(machines []gohorizon.MachineInfo) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, machine := range machines {
+		if machine.GetState() != "PROVISIONING_ERROR" && machine.GetState() != "ERROR" {
+			continue
+		}
+
+		machineData := machine.GetManagedMachineData()
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("machine %s is in state %s", machine.GetName(), machine.GetState()),
+			Detail:   machineData.GetCloneErrorMessage(),
+		})
+	}
+
+	return diags
+}
+
+// diffStringSets compares the current and desired members of a set, such as the SIDs of an
+// entitlement, and returns the members that need to be added and removed to reconcile them.
+func diffStringSets(current, desired []string) (add, remove []string) {
+	currentSet := map[string]bool{}
+	for _, member := range current {
+		currentSet[member] = true
+	}
+
+	desiredSet := map[string]bool{}
+	for _, member := range desired {
+		desiredSet[member] = true
+		if !currentSet[member] {
+			add = append(add, member)
+		}
+	}
+
+	for _, member := range current {
+		if !desiredSet[member] {
+			remove = append(remove, member)
+		}
+	}
+
+	return add, remove
+}
+
+// expandStringSet converts a set of strings from the schema to a string slice.
+func expandStringSet(set *schema.Set) []string {
+	values := []string{}
+	for _, value := range set.List() {
+		values = append(values, value.(string))
+	}
+
+	return values
+}
@@ -2,13 +2,16 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/umich-vci/gohorizon"
 )
 
@@ -57,30 +60,136 @@ func New(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("HORIZON_HOST", nil),
 					Description: "This is the hostname or IP address of the VMware Horizon server. This must be provided in the config or in the environment variable `HORIZON_HOST`.",
 				},
+				"horizon_port": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("HORIZON_PORT", 0),
+					ValidateFunc: validation.IsPortNumberOrZero,
+					Description:  "Port of the VMware Horizon server, if it does not listen on the default HTTPS port. This can also be provided in the environment variable `HORIZON_PORT`.",
+				},
 				"ssl_verify": {
 					Type:        schema.TypeBool,
 					Optional:    true,
 					Default:     true,
 					Description: "Verify the SSL certificate of the VMware Horizon server?",
 				},
+				"ca_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HORIZON_CA_FILE", ""),
+					Description: "Path to a PEM encoded CA bundle used to verify the certificate of the VMware Horizon server instead of the system CAs. Conflicts with `ca_pem`. This can also be provided in the environment variable `HORIZON_CA_FILE`.",
+				},
+				"ca_pem": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "PEM encoded CA bundle used to verify the certificate of the VMware Horizon server instead of the system CAs. Conflicts with `ca_file`.",
+				},
+				"client_cert_pem": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "PEM encoded client certificate to present to the VMware Horizon server. `client_key_pem` must also be set.",
+				},
+				"client_key_pem": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "PEM encoded private key of `client_cert_pem`.",
+				},
+				"min_tls_version": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "1.2",
+					ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+					Description:  "Minimum TLS version to use when connecting to the VMware Horizon server. One of `1.0`, `1.1`, `1.2` or `1.3`.",
+				},
+				"tls_server_name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Server name used to verify the certificate of the VMware Horizon server, if it differs from `horizon_host`.",
+				},
+				"proxy_url": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("HORIZON_PROXY_URL", ""),
+					ValidateFunc: validation.Any(validation.StringIsEmpty, validation.IsURLWithScheme([]string{"http", "https", "socks5"})),
+					Description:  "URL of the proxy used to reach the VMware Horizon server. If unset, the `HTTPS_PROXY` and `NO_PROXY` environment variables are honored. This can also be provided in the environment variable `HORIZON_PROXY_URL`.",
+				},
+				"request_timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Number of seconds a single request to the VMware Horizon server may take, including reading the response. Each retry gets the full timeout. 0 means no timeout.",
+				},
+				"max_idle_conns": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      100,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Maximum number of idle connections kept open to the VMware Horizon server. 0 means no limit.",
+				},
+				"max_idle_conns_per_host": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      10,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Maximum number of idle connections kept open per host.",
+				},
+				"idle_conn_timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      90,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Number of seconds an idle connection is kept open before it is closed. 0 means no limit.",
+				},
+				"max_retries": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("HORIZON_MAX_RETRIES", 5),
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Maximum number of times a request is retried when the VMware Horizon server is busy or unreachable. Requests that are not idempotent are only retried when the server did not process them. This can also be provided in the environment variable `HORIZON_MAX_RETRIES`. Defaults to 5.",
+				},
+				"max_retry_wait": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("HORIZON_MAX_RETRY_WAIT", 30),
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Maximum number of seconds to wait between retries. This also caps the wait requested by a `Retry-After` header. This can also be provided in the environment variable `HORIZON_MAX_RETRY_WAIT`. Defaults to 30.",
+				},
 			},
 			DataSourcesMap: map[string]*schema.Resource{
 				"horizon_active_directory_domain":               dataSourceActiveDirectoryDomain(),
 				"horizon_active_directory_domain_user_or_group": dataSourceActiveDirectoryDomainUserOrGroup(),
+				"horizon_image_management_stream":               dataSourceImageManagementStream(),
+				"horizon_image_management_tag":                  dataSourceImageManagementTag(),
+				"horizon_image_management_version":              dataSourceImageManagementVersion(),
 				"horizon_instant_clone_domain_account":          dataSourceInstantCloneDomainAccount(),
 				"horizon_local_access_group":                    dataSourceLocalAccessGroup(),
 				"horizon_vcenter_base_vm":                       dataSourcevCenterBaseVM(),
 				"horizon_vcenter_base_vm_snapshot":              dataSourcevCenterBaseVMSnapshot(),
+				"horizon_vcenter_customization_spec":            dataSourcevCenterCustomizationSpec(),
 				"horizon_vcenter_datacenter":                    dataSourcevCenterDatacenter(),
 				"horizon_vcenter_datastore":                     dataSourcevCenterDatastore(),
 				"horizon_vcenter_host_or_cluster":               dataSourcevCenterHostOrCluster(),
+				"horizon_vcenter_network_label":                 dataSourcevCenterNetworkLabel(),
 				"horizon_vcenter_resource_pool":                 dataSourcevCenterResourcePool(),
 				"horizon_vcenter_server":                        dataSourcevCenter(),
 				"horizon_vcenter_vm_folder":                     dataSourcevCenterVMFolder(),
+				"horizon_vcenter_vm_template":                   dataSourcevCenterVMTemplate(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
-				"horizon_desktop_pool_automated":    resourceDesktopPoolAutomated(),
-				"horizon_desktop_pool_entitlements": resourceDesktopPoolEntitlements(),
+				"horizon_application_pool":                resourceApplicationPool(),
+				"horizon_application_pool_entitlements":   resourceApplicationPoolEntitlements(),
+				"horizon_desktop_pool_automated":          resourceDesktopPoolAutomated(),
+				"horizon_desktop_pool_entitlement_member": resourceDesktopPoolEntitlementMember(),
+				"horizon_desktop_pool_entitlements":       resourceDesktopPoolEntitlements(),
+				"horizon_desktop_pool_manual":             resourceDesktopPoolManual(),
+				"horizon_desktop_pool_rds":                resourceDesktopPoolRDS(),
+				"horizon_farm_automated":                  resourceFarmAutomated(),
+				"horizon_farm_manual":                     resourceFarmManual(),
+				"horizon_global_application_entitlement":  resourceGlobalApplicationEntitlement(),
+				"horizon_global_desktop_entitlement":      resourceGlobalDesktopEntitlement(),
+				"horizon_global_entitlement_users":        resourceGlobalEntitlementUsers(),
 			},
 		}
 
@@ -92,6 +201,9 @@ func New(version string) func() *schema.Provider {
 
 type apiClient struct {
 	Client gohorizon.APIClient
+
+	// session holds the access and refresh tokens the client authenticates with.
+	session *horizonSession
 }
 
 func configure(version string, p *schema.Provider) func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -102,29 +214,53 @@ func configure(version string, p *schema.Provider) func(context.Context, *schema
 		password := d.Get("password").(string)
 		domain := d.Get("domain").(string)
 		host := d.Get("horizon_host").(string)
-		sslVerify := d.Get("ssl_verify").(bool)
-
-		config := gohorizon.NewConfiguration()
-		config.UserAgent = userAgent
-		config.Host = host
-		config.Scheme = "https"
-
-		if !sslVerify {
-			tr := &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			}
-			config.HTTPClient = &http.Client{Transport: tr}
+		if port := d.Get("horizon_port").(int); port != 0 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
 		}
+		requestTimeout := time.Duration(d.Get("request_timeout").(int)) * time.Second
+		maxRetries := d.Get("max_retries").(int)
+		maxRetryWait := time.Duration(d.Get("max_retry_wait").(int)) * time.Second
 
-		client := gohorizon.NewAPIClient(config)
-
-		body := gohorizon.NewAuthLogin(domain, password, username)
-		tokens, _, err := client.AuthApi.LoginUser(ctx).Body(*body).Execute()
+		baseTransport, err := newTransport(d)
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
 
-		client.GetConfig().AddDefaultHeader("Authorization", fmt.Sprintf("Bearer %s", *tokens.AccessToken))
-		return &apiClient{Client: *client}, nil
+		var transport http.RoundTripper = &retryTransport{
+			base:           baseTransport,
+			maxRetries:     maxRetries,
+			maxWait:        maxRetryWait,
+			attemptTimeout: requestTimeout,
+		}
+
+		// the auth client talks to the connection server directly so refreshing a token does not need a token
+		authClient := gohorizon.NewAPIClient(newConfiguration(host, userAgent, &http.Client{
+			Transport: transport,
+		}))
+
+		session, err := newHorizonSession(ctx, authClient.AuthApi, domain, username, password)
+		if err != nil {
+			return nil, returnResponseErr(nil, err)
+		}
+		session.logoutAPI = gohorizon.NewAPIClient(newConfiguration(host, userAgent, &http.Client{
+			Transport: baseTransport,
+		})).AuthApi
+		trackSession(session)
+
+		client := gohorizon.NewAPIClient(newConfiguration(host, userAgent, &http.Client{
+			Transport: &authTransport{base: transport, session: session},
+		}))
+
+		return &apiClient{Client: *client, session: session}, nil
 	}
 }
+
+func newConfiguration(host, userAgent string, httpClient *http.Client) *gohorizon.Configuration {
+	config := gohorizon.NewConfiguration()
+	config.UserAgent = userAgent
+	config.Host = host
+	config.Scheme = "https"
+	config.HTTPClient = httpClient
+
+	return config
+}
@@ -1,16 +1,28 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/umich-vci/gohorizon"
 )
 
 // providerFactories are used to instantiate a provider during acceptance testing.
 // The factory function will be invoked for every Terraform CLI command executed
 // to create a provider server to which the CLI can reattach.
 var providerFactories = map[string]func() (*schema.Provider, error){
-	"scaffolding": func() (*schema.Provider, error) {
+	"horizon": func() (*schema.Provider, error) {
 		return New("dev")(), nil
 	},
 }
@@ -26,3 +38,178 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// testJWT returns an unsigned JWT access token that expires at exp.
+func testJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+
+	return "e30." + payload + ".c2ln"
+}
+
+// newTestHorizonServer starts a fake Horizon Connection Server. The login, refresh and logout APIs
+// succeed unless they are overridden by handlers, which are keyed by path.
+func newTestHorizonServer(t *testing.T, handlers map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	routes := map[string]http.HandlerFunc{
+		"/rest/login": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, map[string]string{
+				"access_token":  testJWT(time.Now().Add(time.Hour)),
+				"refresh_token": "refresh",
+			})
+		},
+		"/rest/refresh": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, map[string]string{"access_token": testJWT(time.Now().Add(time.Hour))})
+		},
+		"/rest/logout": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+	}
+	for path, handler := range handlers {
+		routes[path] = handler
+	}
+
+	mux := http.NewServeMux()
+	for path, handler := range routes {
+		mux.HandleFunc(path, handler)
+	}
+
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// testProviderConfig returns a provider block that connects to a fake Horizon server.
+func testProviderConfig(srv *httptest.Server) string {
+	return fmt.Sprintf(`
+provider "horizon" {
+  username     = "user"
+  password     = "password"
+  domain       = "example"
+  horizon_host = %q
+  ssl_verify   = false
+}
+`, srv.Listener.Addr().String())
+}
+
+// testResourceDataUpdate returns the data of resource r with ID id that is being updated from state to config.
+func testResourceDataUpdate(t *testing.T, r *schema.Resource, id string, state, config map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+
+	old := schema.TestResourceDataRaw(t, r.Schema, state)
+	old.SetId(id)
+
+	diff, err := r.Diff(context.Background(), old.State(), terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("error computing the diff: %s", err)
+	}
+
+	d, err := schema.InternalMap(r.Schema).Data(old.State(), diff)
+	if err != nil {
+		t.Fatalf("error creating the resource data: %s", err)
+	}
+
+	return d
+}
+
+// testPlan plans config and checks that it fails with expectError, or that it succeeds with changes
+// when expectError is empty.
+func testPlan(t *testing.T, config, expectError string) {
+	t.Helper()
+
+	step := resource.TestStep{
+		Config:             config,
+		PlanOnly:           true,
+		ExpectNonEmptyPlan: true,
+	}
+	if expectError != "" {
+		step.ExpectError = regexp.MustCompile(regexp.QuoteMeta(expectError))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps:             []resource.TestStep{step},
+	})
+}
+
+func writeTestJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// testAPIClient returns a client for a fake Horizon server, as passed to the resources by configure.
+func testAPIClient(srv *httptest.Server) *apiClient {
+	config := gohorizon.NewConfiguration()
+	config.Host = srv.Listener.Addr().String()
+	config.Scheme = "https"
+	config.HTTPClient = srv.Client()
+
+	return &apiClient{Client: *gohorizon.NewAPIClient(config)}
+}
+
+// testReadCase is a Read test of a resource whose API object is served by a fake Horizon server.
+type testReadCase struct {
+	name string
+	// id is the ID of the resource, if it differs from the one passed to testResourceRead.
+	id string
+	// object is the API object, or nil if it was deleted outside of Terraform.
+	object map[string]interface{}
+	// raw is the configuration of the resource that is read.
+	raw       map[string]interface{}
+	wantID    string
+	wantError string
+	// check is called after a successful Read that kept the resource in state.
+	check func(t *testing.T, d *schema.ResourceData)
+}
+
+// testResourceRead runs the Read of resource r with ID id for each case. The object of the case is
+// served at path, next to handlers.
+func testResourceRead(t *testing.T, r *schema.Resource, id, path string, handlers map[string]http.HandlerFunc, cases []testReadCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			routes := map[string]http.HandlerFunc{
+				path: func(w http.ResponseWriter, r *http.Request) {
+					if tc.object == nil {
+						writeTestJSON(w, http.StatusNotFound, map[string]string{"error_key": "not.found", "error_message": "Object not found"})
+						return
+					}
+					writeTestJSON(w, http.StatusOK, tc.object)
+				},
+			}
+			for p, handler := range handlers {
+				routes[p] = handler
+			}
+			srv := newTestHorizonServer(t, routes)
+
+			raw := tc.raw
+			if raw == nil {
+				raw = map[string]interface{}{}
+			}
+			d := schema.TestResourceDataRaw(t, r.Schema, raw)
+			d.SetId(id)
+			if tc.id != "" {
+				d.SetId(tc.id)
+			}
+
+			diags := r.ReadContext(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if d.Id() != tc.wantID {
+				t.Errorf("ID = %q, want %q", d.Id(), tc.wantID)
+			}
+			if tc.check != nil && tc.wantError == "" && tc.wantID != "" {
+				tc.check(t, d)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceApplicationPool() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage Horizon Application Pools. An application pool publishes an application from either an RDS farm or a desktop pool whose session_type allows applications.",
+
+		CreateContext: resourceApplicationPoolCreate,
+		ReadContext:   resourceApplicationPoolRead,
+		UpdateContext: resourceApplicationPoolUpdate,
+		DeleteContext: resourceApplicationPoolDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"executable_path": {
+				Description: "Path to the application executable.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "The application pool name is the unique identifier used to identify this application pool. This property must contain only alphanumerics, underscores, and dashes. The maximum length is 64 characters.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"desktop_pool_id": {
+				Description:  "ID of the desktop pool from which this application pool is created. The session_type of the desktop pool must be APPLICATION or DESKTOP_AND_APPLICATION. Exactly one of desktop_pool_id or farm_id must be set.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"desktop_pool_id", "farm_id"},
+			},
+			"farm_id": {
+				Description:  "ID of the farm from which this application pool is created. Exactly one of desktop_pool_id or farm_id must be set.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"desktop_pool_id", "farm_id"},
+			},
+			"category_folder_name": {
+				Description:  "Name of the category folder in the user's OS containing a shortcut to the application. Unset if the application does not belong to a category. shortcut_locations must also be set.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"shortcut_locations"},
+			},
+			"cloud_brokered": {
+				Description: "Indicates whether the application pool is brokered by Horizon Cloud Services.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"cs_restriction_tags": {
+				Description: "List of Connection server restriction tags to which the access to the application pool is restricted. If this property is not set it indicates that the application pool can be accessed from any connection server.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"description": {
+				Description: "Description of the application pool.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"display_name": {
+				Description: "The display name is the name that users will see in Horizon client. If the display name is left blank, it defaults to name.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"enable_client_restrictions": {
+				Description: "Indicates whether client restrictions are to be applied to the application pool. Only valid for application pools created from a farm.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"enable_pre_launch": {
+				Description: "Indicates whether to pre-launch the application.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"enabled": {
+				Description: "Indicates whether the application pool is enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"max_multi_sessions": {
+				Description:  "Maximum number of multi-sessions a user can have in this application pool. This is only applicable when multi_session_mode is not DISABLED.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"multi_session_mode": {
+				Description:  "Multi-session mode for the application pool. DISABLED: Multi-session is not supported for this application. ENABLED_DEFAULT_OFF: Multi-session is supported but is disabled by default. ENABLED_DEFAULT_ON: Multi-session is supported and is enabled by default. ENABLED_ENFORCED: Multi-session is supported and it is enforced.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "DISABLED",
+				ValidateFunc: validation.StringInSlice([]string{"DISABLED", "ENABLED_DEFAULT_OFF", "ENABLED_DEFAULT_ON", "ENABLED_ENFORCED"}, false),
+			},
+			"parameters": {
+				Description: "Parameters to pass to the application when launching.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"publisher": {
+				Description: "Application publisher.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"shortcut_locations": {
+				Description: "Locations of the category folder in the user's OS containing a shortcut to the application. This is required if category_folder_name is set.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"START_MENU", "DESKTOP"}, false),
+				},
+			},
+			"start_folder": {
+				Description: "Starting folder for the application.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"version": {
+				Description: "Application version.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"access_group_id": {
+				Description: "Access group of the application pool, inherited from the farm or desktop pool.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"global_application_entitlement_id": {
+				Description: "ID of the global application entitlement the application pool belongs to, if any.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceApplicationPoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	execPath := d.Get("executable_path").(string)
+	cloudBrokered := d.Get("cloud_brokered").(bool)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	preLaunch := d.Get("enable_pre_launch").(bool)
+	enabled := d.Get("enabled").(bool)
+	multiSessionMode := d.Get("multi_session_mode").(string)
+
+	body := gohorizon.NewApplicationPoolCreateSpecV2(execPath, name)
+	body.CloudBrokered = &cloudBrokered
+	body.EnableClientRestrictions = &clientRestrictions
+	body.EnablePreLaunch = &preLaunch
+	body.Enabled = &enabled
+	body.MultiSessionMode = &multiSessionMode
+
+	if multiSessionMode != "DISABLED" {
+		maxSessions := int32(d.Get("max_multi_sessions").(int))
+		body.MaxMultiSessions = &maxSessions
+	}
+
+	if fid, ok := d.GetOk("farm_id"); ok {
+		farmID := fid.(string)
+		body.FarmId = &farmID
+	}
+
+	if dpid, ok := d.GetOk("desktop_pool_id"); ok {
+		poolID := dpid.(string)
+
+		poolInfo, resp, err := client.InventoryApi.GetDesktopPoolV5(ctx, poolID).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+
+		switch poolInfo.GetSessionType() {
+		case "APPLICATION", "DESKTOP_AND_APPLICATION":
+		default:
+			return diag.Errorf("desktop pool %s has session_type \"%s\" which does not allow applications", poolID, poolInfo.GetSessionType())
+		}
+
+		body.DesktopPoolId = &poolID
+	}
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	if tags, ok := d.GetOk("cs_restriction_tags"); ok {
+		csTags := expandStringSet(tags.(*schema.Set))
+		body.CsRestrictionTags = &csTags
+	}
+
+	if desc, ok := d.GetOk("description"); ok {
+		description := desc.(string)
+		body.Description = &description
+	}
+
+	if dn, ok := d.GetOk("display_name"); ok {
+		displayName := dn.(string)
+		body.DisplayName = &displayName
+	}
+
+	if params, ok := d.GetOk("parameters"); ok {
+		parameters := params.(string)
+		body.Parameters = &parameters
+	}
+
+	if pub, ok := d.GetOk("publisher"); ok {
+		publisher := pub.(string)
+		body.Publisher = &publisher
+	}
+
+	if sl, ok := d.GetOk("shortcut_locations"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocations = &shortcutLocations
+	}
+
+	if sf, ok := d.GetOk("start_folder"); ok {
+		startFolder := sf.(string)
+		body.StartFolder = &startFolder
+	}
+
+	if ver, ok := d.GetOk("version"); ok {
+		version := ver.(string)
+		body.Version = &version
+	}
+
+	resp, err := client.InventoryApi.CreateApplicationPoolV2(ctx).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"name\",\"value\":\"%s\"}", name)
+	appPools, resp, err := client.InventoryApi.ListApplicationPoolsV3(ctx).Filter(filter).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	switch len(appPools) {
+	case 0:
+		return diag.Errorf("could not find ID of application pool that was created")
+	case 1:
+		d.SetId(*appPools[0].Id)
+	default:
+		return diag.Errorf("Multiple application pools found with same name - should not be possible")
+	}
+
+	return resourceApplicationPoolRead(ctx, d, meta)
+}
+
+func resourceApplicationPoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	appPool, resp, err := client.InventoryApi.GetApplicationPoolV3(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the application pool was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.Set("access_group_id", appPool.AccessGroupId)
+	d.Set("category_folder_name", appPool.CategoryFolderName)
+	d.Set("cloud_brokered", appPool.CloudBrokered)
+	d.Set("cs_restriction_tags", appPool.CsRestrictionTags)
+	d.Set("description", appPool.Description)
+	d.Set("desktop_pool_id", appPool.DesktopPoolId)
+	d.Set("display_name", appPool.DisplayName)
+	d.Set("enable_client_restrictions", appPool.EnableClientRestrictions)
+	d.Set("enable_pre_launch", appPool.EnablePreLaunch)
+	d.Set("enabled", appPool.Enabled)
+	d.Set("executable_path", appPool.ExecutablePath)
+	d.Set("farm_id", appPool.FarmId)
+	d.Set("global_application_entitlement_id", appPool.GlobalApplicationEntitlementId)
+	d.Set("multi_session_mode", appPool.MultiSessionMode)
+	d.Set("name", appPool.Name)
+	d.Set("parameters", appPool.Parameters)
+	d.Set("publisher", appPool.Publisher)
+	d.Set("shortcut_locations", appPool.ShortcutLocations)
+	d.Set("start_folder", appPool.StartFolder)
+	d.Set("version", appPool.Version)
+
+	// the server only tracks max_multi_sessions while multi-session is enabled
+	if appPool.GetMultiSessionMode() != "DISABLED" {
+		d.Set("max_multi_sessions", appPool.MaxMultiSessions)
+	}
+
+	return nil
+}
+
+func resourceApplicationPoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	execPath := d.Get("executable_path").(string)
+	preLaunch := d.Get("enable_pre_launch").(bool)
+	enabled := d.Get("enabled").(bool)
+	cloudBrokered := d.Get("cloud_brokered").(bool)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	multiSessionMode := d.Get("multi_session_mode").(string)
+	description := d.Get("description").(string)
+	displayName := d.Get("display_name").(string)
+	parameters := d.Get("parameters").(string)
+	publisher := d.Get("publisher").(string)
+	startFolder := d.Get("start_folder").(string)
+	version := d.Get("version").(string)
+
+	body := gohorizon.NewApplicationPoolUpdateSpecV2(preLaunch, enabled, execPath)
+	body.CloudBrokered = &cloudBrokered
+	body.EnableClientRestrictions = &clientRestrictions
+	body.MultiSessionMode = &multiSessionMode
+	body.Description = &description
+	body.DisplayName = &displayName
+	body.Parameters = &parameters
+	body.Publisher = &publisher
+	body.StartFolder = &startFolder
+	body.Version = &version
+
+	if multiSessionMode != "DISABLED" {
+		maxSessions := int32(d.Get("max_multi_sessions").(int))
+		body.MaxMultiSessions = &maxSessions
+	}
+
+	// an empty category folder name removes the application from its category
+	categoryFolder := d.Get("category_folder_name").(string)
+	body.CategoryFolderName = &categoryFolder
+
+	csTags := expandStringSet(d.Get("cs_restriction_tags").(*schema.Set))
+	body.CsRestrictionTags = &csTags
+
+	if sl, ok := d.GetOk("shortcut_locations"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocations = &shortcutLocations
+	}
+
+	resp, err := client.InventoryApi.UpdateApplicationPoolV2(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	return resourceApplicationPoolRead(ctx, d, meta)
+}
+
+func resourceApplicationPoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	resp, err := client.InventoryApi.DeleteApplicationPool(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
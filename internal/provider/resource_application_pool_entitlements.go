@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceApplicationPoolEntitlements() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for managing application pool entitlements in Horizon.",
+
+		CreateContext: resourceApplicationPoolEntitlementsCreate,
+		ReadContext:   resourceApplicationPoolEntitlementsRead,
+		UpdateContext: resourceApplicationPoolEntitlementsUpdate,
+		DeleteContext: resourceApplicationPoolEntitlementsDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"ad_user_or_group_ids": {
+				Description: "List of ad-user-or-group SIDs for the entitlement operations on the given application pool.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"application_pool_id": {
+				Description: "Unique ID representing the application pool.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceApplicationPoolEntitlementsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	appPoolID := d.Get("application_pool_id").(string)
+	adIDs := expandStringSet(d.Get("ad_user_or_group_ids").(*schema.Set))
+
+	bodyElem := gohorizon.NewEntitlementSpec()
+	bodyElem.Id = &appPoolID
+	bodyElem.AdUserOrGroupIds = &adIDs
+	body := []gohorizon.EntitlementSpec{*bodyElem}
+
+	results, resp, err := client.EntitlementsApi.BulkCreateApplicationPoolEntitlements(ctx).Body(body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
+	}
+
+	d.SetId(appPoolID)
+
+	return resourceApplicationPoolEntitlementsRead(ctx, d, meta)
+}
+
+func resourceApplicationPoolEntitlementsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	appPoolID := d.Id()
+
+	entitlement, resp, err := client.EntitlementsApi.GetApplicationPoolEntitlements(ctx, appPoolID).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the application pool was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.Set("application_pool_id", appPoolID)
+	d.Set("ad_user_or_group_ids", entitlement.AdUserOrGroupIds)
+
+	return nil
+}
+
+func resourceApplicationPoolEntitlementsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	appPoolID := d.Id()
+	adIDs := expandStringSet(d.Get("ad_user_or_group_ids").(*schema.Set))
+
+	currentADIDs, resp, err := client.EntitlementsApi.GetApplicationPoolEntitlements(ctx, appPoolID).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	addList, removeList := diffStringSets(currentADIDs.GetAdUserOrGroupIds(), adIDs)
+
+	if len(removeList) > 0 {
+		removeBodyElem := gohorizon.NewEntitlementSpec()
+		removeBodyElem.Id = &appPoolID
+		removeBodyElem.AdUserOrGroupIds = &removeList
+		removeBody := []gohorizon.EntitlementSpec{*removeBodyElem}
+		results, resp, err := client.EntitlementsApi.BulkDeleteApplicationPoolEntitlements(ctx).Body(removeBody).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkEntitlementResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	if len(addList) > 0 {
+		addBodyElem := gohorizon.NewEntitlementSpec()
+		addBodyElem.Id = &appPoolID
+		addBodyElem.AdUserOrGroupIds = &addList
+		addBody := []gohorizon.EntitlementSpec{*addBodyElem}
+		results, resp, err := client.EntitlementsApi.BulkCreateApplicationPoolEntitlements(ctx).Body(addBody).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkEntitlementResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	return resourceApplicationPoolEntitlementsRead(ctx, d, meta)
+}
+
+func resourceApplicationPoolEntitlementsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	appPoolID := d.Get("application_pool_id").(string)
+	adIDs := expandStringSet(d.Get("ad_user_or_group_ids").(*schema.Set))
+
+	bodyElem := gohorizon.NewEntitlementSpec()
+	bodyElem.Id = &appPoolID
+	bodyElem.AdUserOrGroupIds = &adIDs
+	body := []gohorizon.EntitlementSpec{*bodyElem}
+
+	results, resp, err := client.EntitlementsApi.BulkDeleteApplicationPoolEntitlements(ctx).Body(body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/umich-vci/gohorizon"
+)
+
+func TestBulkEntitlementResponseErrors(t *testing.T) {
+	status := func(code int32) *int32 { return &code }
+	str := func(s string) *string { return &s }
+
+	cases := []struct {
+		name        string
+		items       []gohorizon.BulkEntitlementResponseInfo
+		wantSummary []string
+	}{
+		{
+			name: "success",
+			items: []gohorizon.BulkEntitlementResponseInfo{{
+				Id:         str("pool"),
+				StatusCode: status(http.StatusOK),
+				Details:    &[]gohorizon.EntitlementResponseInfo{{AdUserOrGroupId: str("S-1-5-21-1"), StatusCode: status(http.StatusOK)}},
+			}},
+		},
+		{
+			name: "failed user or group",
+			items: []gohorizon.BulkEntitlementResponseInfo{{
+				Id:         str("pool"),
+				StatusCode: status(http.StatusMultiStatus),
+				Details: &[]gohorizon.EntitlementResponseInfo{
+					{AdUserOrGroupId: str("S-1-5-21-1"), StatusCode: status(http.StatusOK)},
+					{AdUserOrGroupId: str("S-1-5-21-2"), StatusCode: status(http.StatusNotFound)},
+					{AdUserOrGroupId: str("S-1-5-21-3"), StatusCode: status(http.StatusConflict)},
+				},
+			}},
+			wantSummary: []string{
+				"entitlement of S-1-5-21-2 to pool failed with status 404",
+				"entitlement of S-1-5-21-3 to pool failed with status 409",
+			},
+		},
+		{
+			name: "failed pool",
+			items: []gohorizon.BulkEntitlementResponseInfo{
+				{Id: str("pool"), StatusCode: status(http.StatusOK)},
+				{Id: str("missing"), StatusCode: status(http.StatusNotFound), ErrorMessages: &[]string{"Application pool not found"}},
+			},
+			wantSummary: []string{"entitlement operation failed for missing with status 404"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diags := bulkEntitlementResponseErrors(tc.items)
+			if len(diags) != len(tc.wantSummary) {
+				t.Fatalf("got %d diagnostics, want %d: %#v", len(diags), len(tc.wantSummary), diags)
+			}
+			for i, d := range diags {
+				if d.Summary != tc.wantSummary[i] {
+					t.Errorf("diagnostic %d summary = %q, want %q", i, d.Summary, tc.wantSummary[i])
+				}
+			}
+		})
+	}
+}
+
+// testEntitlementsHandler serves bulk entitlement APIs that entitle the SIDs in entitled, except for
+// the SIDs in failing.
+func testEntitlementsHandler(t *testing.T, entitled *[]string, failing map[string]bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var specs []gohorizon.EntitlementSpec
+		if err := json.NewDecoder(r.Body).Decode(&specs); err != nil {
+			t.Errorf("error decoding the entitlement specs: %s", err)
+		}
+
+		results := []map[string]interface{}{}
+		for _, spec := range specs {
+			details := []map[string]interface{}{}
+			for _, sid := range spec.GetAdUserOrGroupIds() {
+				if failing[sid] {
+					details = append(details, map[string]interface{}{"ad_user_or_group_id": sid, "status_code": http.StatusNotFound})
+					continue
+				}
+				details = append(details, map[string]interface{}{"ad_user_or_group_id": sid, "status_code": http.StatusOK})
+
+				switch r.Method {
+				case http.MethodPost:
+					*entitled = append(*entitled, sid)
+				case http.MethodDelete:
+					remaining := []string{}
+					for _, e := range *entitled {
+						if e != sid {
+							remaining = append(remaining, e)
+						}
+					}
+					*entitled = remaining
+				}
+			}
+			results = append(results, map[string]interface{}{"id": spec.GetId(), "status_code": http.StatusOK, "details": details})
+		}
+		sort.Strings(*entitled)
+
+		writeTestJSON(w, http.StatusOK, results)
+	}
+}
+
+func TestResourceApplicationPoolEntitlementsCreate(t *testing.T) {
+	cases := []struct {
+		name         string
+		failing      map[string]bool
+		wantError    string
+		wantEntitled []string
+	}{
+		{
+			name:         "success",
+			wantEntitled: []string{"S-1-5-21-1", "S-1-5-21-2"},
+		},
+		{
+			name:         "failed group",
+			failing:      map[string]bool{"S-1-5-21-2": true},
+			wantError:    "entitlement of S-1-5-21-2 to app failed with status 404",
+			wantEntitled: []string{"S-1-5-21-1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entitled := []string{}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/entitlements/v1/application-pools": testEntitlementsHandler(t, &entitled, tc.failing),
+				"/rest/entitlements/v1/application-pools/app": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "app", "ad_user_or_group_ids": entitled})
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceApplicationPoolEntitlements().Schema, map[string]interface{}{
+				"application_pool_id":  "app",
+				"ad_user_or_group_ids": []interface{}{"S-1-5-21-1", "S-1-5-21-2"},
+			})
+
+			diags := resourceApplicationPoolEntitlementsCreate(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+				if d.Id() != "" {
+					t.Errorf("ID = %q, want the failed entitlements kept out of state", d.Id())
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(entitled, tc.wantEntitled) {
+				t.Errorf("entitled = %v, want %v", entitled, tc.wantEntitled)
+			}
+		})
+	}
+}
+
+func TestResourceApplicationPoolEntitlementsUpdate(t *testing.T) {
+	cases := []struct {
+		name         string
+		failing      map[string]bool
+		wantError    string
+		wantEntitled []string
+	}{
+		{
+			name:         "success",
+			wantEntitled: []string{"S-1-5-21-2", "S-1-5-21-3"},
+		},
+		{
+			name:         "failed removal",
+			failing:      map[string]bool{"S-1-5-21-1": true},
+			wantError:    "entitlement of S-1-5-21-1 to app failed with status 404",
+			wantEntitled: []string{"S-1-5-21-1", "S-1-5-21-2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entitled := []string{"S-1-5-21-1", "S-1-5-21-2"}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/entitlements/v1/application-pools": testEntitlementsHandler(t, &entitled, tc.failing),
+				"/rest/entitlements/v1/application-pools/app": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "app", "ad_user_or_group_ids": entitled})
+				},
+			})
+
+			d := testResourceDataUpdate(t, resourceApplicationPoolEntitlements(), "app", map[string]interface{}{
+				"application_pool_id":  "app",
+				"ad_user_or_group_ids": []interface{}{"S-1-5-21-1", "S-1-5-21-2"},
+			}, map[string]interface{}{
+				"application_pool_id":  "app",
+				"ad_user_or_group_ids": []interface{}{"S-1-5-21-2", "S-1-5-21-3"},
+			})
+
+			diags := resourceApplicationPoolEntitlementsUpdate(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(entitled, tc.wantEntitled) {
+				t.Errorf("entitled = %v, want %v", entitled, tc.wantEntitled)
+			}
+		})
+	}
+}
+
+func TestResourceApplicationPoolEntitlementsRead(t *testing.T) {
+	testResourceRead(t, resourceApplicationPoolEntitlements(), "app", "/rest/entitlements/v1/application-pools/app", nil, []testReadCase{
+		{
+			name:   "application pool deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:   "entitled groups",
+			object: map[string]interface{}{"id": "app", "ad_user_or_group_ids": []string{"S-1-5-21-1", "S-1-5-21-2"}},
+			wantID: "app",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := sortedStringSet(d.Get("ad_user_or_group_ids").(*schema.Set)); !reflect.DeepEqual(got, []string{"S-1-5-21-1", "S-1-5-21-2"}) {
+					t.Errorf("ad_user_or_group_ids = %v, want the entitled groups", got)
+				}
+			},
+		},
+	})
+}
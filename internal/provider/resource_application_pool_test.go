@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceApplicationPoolPlan(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      string
+		expectError string
+	}{
+		{
+			name: "valid farm application",
+			config: `
+resource "horizon_application_pool" "test" {
+  name                 = "notepad"
+  executable_path      = "C:\\Windows\\notepad.exe"
+  farm_id              = "farm"
+  category_folder_name = "Tools"
+  shortcut_locations   = ["START_MENU"]
+}
+`,
+		},
+		{
+			name: "category folder without shortcut locations",
+			config: `
+resource "horizon_application_pool" "test" {
+  name                 = "notepad"
+  executable_path      = "C:\\Windows\\notepad.exe"
+  farm_id              = "farm"
+  category_folder_name = "Tools"
+}
+`,
+			expectError: "all of `category_folder_name,shortcut_locations`",
+		},
+		{
+			name: "desktop pool and farm",
+			config: `
+resource "horizon_application_pool" "test" {
+  name            = "notepad"
+  executable_path = "C:\\Windows\\notepad.exe"
+  desktop_pool_id = "pool"
+  farm_id         = "farm"
+}
+`,
+			expectError: "only one of `desktop_pool_id,farm_id` can be specified",
+		},
+		{
+			name: "neither desktop pool nor farm",
+			config: `
+resource "horizon_application_pool" "test" {
+  name            = "notepad"
+  executable_path = "C:\\Windows\\notepad.exe"
+}
+`,
+			expectError: "one of `desktop_pool_id,farm_id` must be specified",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHorizonServer(t, nil)
+			testPlan(t, testProviderConfig(srv)+tc.config, tc.expectError)
+		})
+	}
+}
+
+func TestResourceApplicationPoolCreateFromDesktopPool(t *testing.T) {
+	cases := []struct {
+		sessionType string
+		wantError   string
+	}{
+		{"APPLICATION", ""},
+		{"DESKTOP_AND_APPLICATION", ""},
+		{"DESKTOP", `has session_type "DESKTOP" which does not allow applications`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.sessionType, func(t *testing.T) {
+			appPool := map[string]interface{}{"id": "app", "name": "notepad", "desktop_pool_id": "pool"}
+
+			created := false
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "pool", "session_type": tc.sessionType})
+				},
+				"/rest/inventory/v2/application-pools": func(w http.ResponseWriter, r *http.Request) {
+					created = true
+					w.WriteHeader(http.StatusCreated)
+				},
+				"/rest/inventory/v3/application-pools": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, []interface{}{appPool})
+				},
+				"/rest/inventory/v3/application-pools/app": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, appPool)
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceApplicationPool().Schema, map[string]interface{}{
+				"name":            "notepad",
+				"executable_path": `C:\Windows\notepad.exe`,
+				"desktop_pool_id": "pool",
+			})
+
+			diags := resourceApplicationPoolCreate(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+				if created {
+					t.Errorf("application pool was created from a desktop pool that does not allow applications")
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+			if d.Id() != "app" {
+				t.Errorf("ID = %q, want %q", d.Id(), "app")
+			}
+		})
+	}
+}
+
+func TestResourceApplicationPoolUpdateClearsCategoryFolder(t *testing.T) {
+	var spec map[string]interface{}
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/inventory/v2/application-pools/app": func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				t.Errorf("error decoding the update spec: %s", err)
+			}
+		},
+		"/rest/inventory/v3/application-pools/app": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "app", "name": "notepad", "farm_id": "farm"})
+		},
+	})
+
+	d := testResourceDataUpdate(t, resourceApplicationPool(), "app", map[string]interface{}{
+		"name":                 "notepad",
+		"executable_path":      `C:\Windows\notepad.exe`,
+		"farm_id":              "farm",
+		"category_folder_name": "Tools",
+		"shortcut_locations":   []interface{}{"START_MENU"},
+	}, map[string]interface{}{
+		"name":            "notepad",
+		"executable_path": `C:\Windows\notepad.exe`,
+		"farm_id":         "farm",
+	})
+
+	if diags := resourceApplicationPoolUpdate(context.Background(), d, testAPIClient(srv)); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	if folder, ok := spec["category_folder_name"]; !ok || folder != "" {
+		t.Errorf("category_folder_name = %v, want an empty name that removes the category", folder)
+	}
+}
+
+func TestResourceApplicationPoolRead(t *testing.T) {
+	testResourceRead(t, resourceApplicationPool(), "app", "/rest/inventory/v3/application-pools/app", nil, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:   "farm application",
+			object: map[string]interface{}{"id": "app", "name": "notepad", "farm_id": "farm", "executable_path": `C:\Windows\notepad.exe`},
+			wantID: "app",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := d.Get("farm_id"); got != "farm" {
+					t.Errorf("farm_id = %v, want %q", got, "farm")
+				}
+			},
+		},
+	})
+}
@@ -3,8 +3,14 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/umich-vci/gohorizon"
@@ -17,12 +23,29 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 		CreateContext: resourceDesktopPoolCreate,
 		ReadContext:   resourceDesktopPoolRead,
 		UpdateContext: resourceDesktopPoolUpdate,
-		DeleteContext: resourceDesktopPoolDelete,
+		DeleteContext: resourceDesktopPoolAutomatedDelete,
+
+		CustomizeDiff: customdiff.All(
+			resourceDesktopPoolDisplayProtocolCustomizeDiff,
+			resourceDesktopPoolUserAssignmentCustomizeDiff,
+			resourceDesktopPoolNamingCustomizeDiff,
+			resourceDesktopPoolSourceCustomizeDiff,
+			resourceDesktopPoolCustomizationCustomizeDiff,
+			resourceDesktopPoolStorageCustomizeDiff,
+			resourceDesktopPoolNicsCustomizeDiff,
+			resourceDesktopPoolSessionSettingsCustomizeDiff,
+		),
 
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"access_group_id": {
 				Description: "Access groups can organize the entities such as desktop pools in the organization. They can also be used for delegated administration.",
@@ -34,10 +57,13 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				// ValidateFunc: validation.StringMatch(),
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[A-Za-z0-9_-]+$`),
+					"name must contain only alphanumerics, underscores, and dashes",
+				),
 			},
 			"naming_method": {
-				Description:  "Naming method for the desktop pool.",
+				Description:  "Naming method for the desktop pool. PATTERN: Machines are named using pattern_naming_settings. SPECIFIED: Machines are named using the list of names in specific_naming_settings.",
 				Type:         schema.TypeString,
 				Required:     true,
 				ForceNew:     true,
@@ -96,6 +122,7 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 				Description:  "Type of customization to use. NONE: Applicable To: Full clone desktop pools. No customization. SYS_PREP: Applicable To: Full clone desktop pools. Microsoft Sysprep is a tool to deploy the configured operating system installation from a base image. The machine can then be customized based on an answer script. Sysprep can modify a larger number of configurable parameters than QuickPrep. CLONE_PREP: Applicable To: Instant clone desktop pools. ClonePrep is a VMware system tool executed by Instant Clone Engine during a instant clone machine deployment. ClonePrep personalizes each machine created from the Master image.",
 				Type:         schema.TypeString,
 				Required:     true,
+				ForceNew:     true,
 				ValidateFunc: validation.StringInSlice([]string{"NONE", "SYS_PREP", "CLONE_PREP"}, false),
 			},
 			"clone_prep_settings": {
@@ -150,7 +177,7 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 				},
 			},
 			"sys_prep_settings": {
-				Description: "Microsoft Sysprep is a tool to deploy the configured operating system installation from a base image. The machine can then be customized based on an answer script. Sysprep can modify a larger number of configurable parameters than QuickPrep.",
+				Description: "Applicable To: Full clone desktop pools. Microsoft Sysprep is a tool to deploy the configured operating system installation from a base image. The machine can then be customized based on an answer script. Sysprep can modify a larger number of configurable parameters than QuickPrep. This is required when customization_type is SYS_PREP.",
 				Type:        schema.TypeList,
 				Optional:    true,
 				MaxItems:    1,
@@ -164,6 +191,34 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 					},
 				},
 			},
+			"delete_settings": {
+				Description: "Settings used when the desktop pool is deleted. If this is not set, the machine VMs of INSTANT_CLONE desktop pools are deleted from vCenter Server and the Horizon API default is used for VIRTUAL_CENTER desktop pools.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"archive_persistent_disks": {
+							Description: "Indicates whether to detach the persistent user disks of the machines and save them for future use. This only applies to linked clone machines with redirected Windows profiles.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"delete_from_disk": {
+							Description: "Indicates whether the machine VMs should be deleted from vCenter Server. This must be true when source is INSTANT_CLONE.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						"force_logoff_sessions": {
+							Description: "Indicates whether active sessions on the machines are logged off before the machines are deleted. Otherwise the delete fails if there are active sessions.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
 			"description": {
 				Description: "Description of the desktop pool.",
 				Type:        schema.TypeString,
@@ -187,69 +242,9 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
-			"display_protocol_settings": {
-				Description: "Display protocol settings.",
-				Type:        schema.TypeList,
-				Optional:    true,
-				MaxItems:    1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"allow_users_to_choose_protocol": {
-							Description: "Indicates whether the users can choose the protocol.",
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     true,
-						},
-						"default_display_protocol": {
-							Description:  "The default display protocol for the desktop pool.",
-							Type:         schema.TypeString,
-							Optional:     true,
-							Default:      "PCOIP",
-							ValidateFunc: validation.StringInSlice([]string{"RDP", "PCOIP", "BLAST"}, false),
-						},
-						"grid_vgpus_enabled": {
-							Description: "When 3D rendering is managed by the vSphere Client, this enables support for NVIDIA GRID vGPUs. This will be false if 3D rendering is not managed by the vSphere Client. If this is true, the host or cluster associated with the desktop pool must support NVIDIA GRID and vGPU types required by the desktop pool's VirtualMachines, VmTemplate or BaseImageSnapshot. If this is false, the desktop pool's VirtualMachines, VmTemplate or BaseImageSnapshot must not support NVIDIA GRID vGPUs. Since suspending VMs with passthrough devices such as vGPUs is not possible, power_policy cannot be set to SUSPEND if this is enabled. Default value is false.",
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-						},
-						"max_number_of_monitors": {
-							Description: "When render3D is disabled, the max_number_of_monitors and max_resolution_of_any_one_monitor settings determine the amount of vRAM assigned to machines in this desktop. The greater these values are, the more memory will be consume on the associated ESX hosts. Existing virtual machines must be powered off and subsequently powered on for the change to take effect. A restart will not cause the changes to take effect. If 3D is enabled and managed by View, the maximum number of monitors must be 1 or 2. For Instant Clones, this value is inherited from snapshot of Master VM. This property is required if renderer3D is set to AUTOMATIC, SOFTWARE, HARDWARE or DISABLED.",
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     2,
-						},
-						"max_resolution_of_any_one_monitor": {
-							Description:  "If 3D rendering is enabled and managed by View, this must be set to the default value. When 3D rendering is disabled, the max_number_of_monitors and max_resolution_of_any_one_monitor settings determine the amount of vRAM assigned to machines in this desktop. The greater these values are, the more memory will be consumed on the associated ESX hosts. This setting is only relevant on managed machines. Existing virtual machines must be powered off and subsequently powered on for the change to take effect. A restart will not cause the changes to take effect. For Instant Clones, this value is inherited from snapshot of Master VM.",
-							Type:         schema.TypeString,
-							Optional:     true,
-							Default:      "WUXGA",
-							ValidateFunc: validation.StringInSlice([]string{"WSXGA_PLUS", "WUXGA", "WQXGA", "UHD", "UHD_5K", "UHD_8K"}, false),
-						},
-						"renderer_3d": {
-							Description:  "3D rendering is supported on Windows 7 or later guests running on VMs with virtual hardware version 8 or later. The default_display_protocol must set to PCOIP and allow_users_to_choose_protocol must be set to false to enable 3D rendering. For instant clone source desktop 3D rendering always mapped to MANAGE_BY_VSPHERE_CLIENT.",
-							Type:         schema.TypeString,
-							Optional:     true,
-							Default:      "DISABLED",
-							ValidateFunc: validation.StringInSlice([]string{"MANAGE_BY_VSPHERE_CLIENT", "AUTOMATIC", "SOFTWARE", "HARDWARE", "DISABLED"}, false),
-						},
-						"session_collaboration_enabled": {
-							Description: "Enable session collaboration feature. Session collaboration allows a user to share their remote session with other users. BLAST must be configured as a supported protocol in supported_display_protocols.",
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     true,
-						},
-						"vram_size_mb": {
-							Description: "vRAM size for View managed 3D rendering. More VRAM can improve 3D performance. Size is in MB. On ESXi 5.0 hosts, the renderer allows a maximum VRAM size of 128MB. On ESXi 5.1 and later hosts, the maximum VRAM size is 512MB. For Instant Clones, this value is inherited from snapshot of Master VM. This property is required if renderer_3d is set to AUTOMATIC, SOFTWARE or HARDWARE.",
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     96,
-						},
-					},
-				},
-			},
+			"display_protocol_settings": desktopPoolDisplayProtocolSettingsSchema(),
 			"do_not_power_on_vms_after_creation": {
-				Description: "Indicates whether to power on VMs after creation. This is the settings when customization will be done manually. This property is required if customization_type is set to NONE with default value as false.",
+				Description: "Applicable To: Full clone desktop pools. Indicates whether to leave VMs powered off after creation. This is the setting to use when customization will be done manually, and can be used when customization_type is NONE or SYS_PREP.",
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
@@ -272,7 +267,54 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 				Optional:    true,
 				Default:     true,
 			},
-			// "nics": {},
+			"nics": {
+				Description: "Applicable To: Instant clone desktop pools. Network interface card settings for machines provisioned for the desktop pool. By default, newly provisioned machines retain the network labels of the parent image on each of their network interface cards. Configuring a NIC here assigns network labels from network_label_assignment_specs to newly provisioned machines instead.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network_interface_card_id": {
+							Description: "The ID of the network interface card of the parent VM these settings apply to.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"network_label_assignment_specs": {
+							Description: "Network labels to assign to the network interface card of newly provisioned machines. Starting at the alphabetically first network label that has not yet been assigned its maximum count, the next provisioned machine is assigned that label. If all network labels have reached their maximum count, further machines are assigned the last label in the list over capacity.",
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"network_label_name": {
+										Description: "The network label for this spec. This is the id of a horizon_vcenter_network_label data source.",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"enabled": {
+										Description: "Indicates whether this specification is enabled. While this specification is disabled, automatic network label assignment will skip over this network label.",
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Default:     true,
+									},
+									"max_label": {
+										Description:  "The maximum number of times this label can be assigned to a machine. This is required if max_label_type is LIMITED.",
+										Type:         schema.TypeInt,
+										Optional:     true,
+										ValidateFunc: validation.IntAtLeast(1),
+									},
+									"max_label_type": {
+										Description:  "Whether there is a maximum limit to the number of times this label may be assigned. UNLIMITED: The label has no limit, and specs after this one in the list will never be used. LIMITED: The label can be assigned at most max_label times.",
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      "UNLIMITED",
+										ValidateFunc: validation.StringInSlice([]string{"UNLIMITED", "LIMITED"}, false),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			"pattern_naming_settings": {
 				Description: "Naming pattern settings for Automated desktop pool.",
 				Type:        schema.TypeList,
@@ -345,7 +387,7 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 							ForceNew:    true,
 						},
 						"base_snapshot_id": {
-							Description: "This property can be set only when source is set to INSTANT_CLONE, vm_template_id is unset and parent_vm_id is set.",
+							Description: "This property can be set only when source is set to INSTANT_CLONE, vm_template_id and im_stream_id are unset and parent_vm_id is set. Changing base_snapshot_id or parent_vm_id of an instant clone desktop pool pushes the new image to the desktop pool.",
 							Type:        schema.TypeString,
 							Optional:    true,
 						},
@@ -353,31 +395,176 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 							Description: "Datacenter within which the desktop pool is configured.",
 							Type:        schema.TypeString,
 							Optional:    true,
+							ForceNew:    true,
 						},
 						"im_stream_id": {
-							Description: "This is required when vm_template_id, parent_vm_id and base_snapshot_id are not set.",
+							Description: "ID of the Image Management Stream the machines are created from. This is required when vm_template_id, parent_vm_id and base_snapshot_id are not set. Changing im_stream_id or im_tag_id of an instant clone desktop pool pushes the new image to the desktop pool.",
 							Type:        schema.TypeString,
 							Optional:    true,
 						},
 						"im_tag_id": {
-							Description: "This is required when im_stream_id is set.",
+							Description: "ID of the Image Management Tag of im_stream_id the machines are created from. This is required when im_stream_id is set.",
 							Type:        schema.TypeString,
 							Optional:    true,
 						},
 						"parent_vm_id": {
-							Description: "This property can be set only when source is set to INSTANT_CLONE.",
+							Description: "This property can be set only when source is set to INSTANT_CLONE and im_stream_id is unset.",
 							Type:        schema.TypeString,
 							Optional:    true,
 						},
 						"vm_template_id": {
-							Description: "Applicable To: Full clone desktop pools. This is required if parent_vm_id and base_snapshot_id are not set.",
+							Description: "Applicable To: Full clone desktop pools. ID of the VM template the machines are cloned from. This is required when source is VIRTUAL_CENTER and im_stream_id is unset.",
 							Type:        schema.TypeString,
 							Optional:    true,
 						},
 					},
 				},
 			},
-			// "session_settings": {},
+			"push_image_settings": {
+				Description: "Applicable To: Instant clone desktop pools. Settings used when a change to parent_vm_id, base_snapshot_id, im_stream_id or im_tag_id pushes a new image to the desktop pool.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"logoff_policy": {
+							Description:  "Determines when to perform the operation on machines which have an active session. FORCE_LOGOFF: Users will be forced to log off when the system is ready to execute the operation. WAIT_FOR_LOGOFF: Wait for connected users to disconnect before the task starts.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "WAIT_FOR_LOGOFF",
+							ValidateFunc: validation.StringInSlice([]string{"FORCE_LOGOFF", "WAIT_FOR_LOGOFF"}, false),
+						},
+						"start_time": {
+							Description:  "When to start the operation, in RFC 3339 format. If unset or the time is in the past, the operation will begin immediately.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsRFC3339Time,
+						},
+						"stop_on_first_error": {
+							Description: "Indicates that the operation should stop on first error.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						"wait_for_completion": {
+							Description: "Indicates whether to wait for the pushed image to be published and the machines to be recreated. If start_time is in the future, this waits until the scheduled operation has finished.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
+			"session_settings": {
+				Description: "Settings related to the sessions of the desktop pool.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_multiple_sessions_per_user": {
+							Description: "Applicable To: Floating desktop pools. Indicates whether multiple sessions are allowed per user.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"allow_users_to_reset_machines": {
+							Description: "Indicates whether the users are allowed to reset or restart their machines.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"delete_or_refresh_machine_after_logoff": {
+							Description:  "Applicable To: Floating desktop pools using pattern naming. Whether machines are to be deleted or refreshed after logoff. For instant clone desktop pools this can only be set to DELETE or NEVER. NEVER: Never delete or refresh the machine in the desktop pool. DELETE: Delete the machine after user logoff. REFRESH: Refresh the machine after user logoff.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "NEVER",
+							ValidateFunc: validation.StringInSlice([]string{"NEVER", "DELETE", "REFRESH"}, false),
+						},
+						"disconnected_session_timeout_minutes": {
+							Description:  "Disconnected sessions timeout (in minutes). This is required if disconnected_session_timeout_policy is set to AFTER.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"disconnected_session_timeout_policy": {
+							Description:  "Log-off policy after disconnected session. IMMEDIATELY: Immediately Logoff after user disconnect. AFTER: Logoff after the specified number of minutes after user disconnect. NEVER: Do not logoff after user disconnect.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "NEVER",
+							ValidateFunc: validation.StringInSlice([]string{"IMMEDIATELY", "AFTER", "NEVER"}, false),
+						},
+						"empty_session_timeout_minutes": {
+							Description:  "Applicable only when session_type is APPLICATION or DESKTOP_AND_APPLICATION. Application empty session timeout (in minutes). An empty session (that has no remote-able window) is disconnected after the timeout. This is required if empty_session_timeout_policy is set to AFTER.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"empty_session_timeout_policy": {
+							Description:  "Applicable only when session_type is APPLICATION or DESKTOP_AND_APPLICATION. Application empty session timeout policy. IMMEDIATE: Empty session will be disconnected immediately. NEVER: Empty session will never disconnected. AFTER: Empty session will be disconnected after specified number of minutes.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "AFTER",
+							ValidateFunc: validation.StringInSlice([]string{"IMMEDIATE", "AFTER", "NEVER"}, false),
+						},
+						"logoff_after_timeout": {
+							Description: "Applicable only when session_type is APPLICATION or DESKTOP_AND_APPLICATION. Indicates whether the empty application sessions are logged off (true) or disconnected (false) after timeout.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"power_policy": {
+							Description:  "Power policy for the machines in the desktop pool after logoff. For instant clone desktop pools this can only be set to ALWAYS_POWERED_ON. If unset, the Horizon default for the desktop pool is used. TAKE_NO_POWER_ACTION: No action will be taken when user logs off. ALWAYS_POWERED_ON: Ensure machines in the desktop pool are always powered on. SUSPEND: Suspend when a user logs off or when the desktop pool is no longer keeping a machine as a spare. POWER_OFF: Power off when a user logs off or when the desktop pool is no longer keeping a machine as a spare.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringInSlice([]string{"TAKE_NO_POWER_ACTION", "ALWAYS_POWERED_ON", "SUSPEND", "POWER_OFF"}, false),
+						},
+						"pre_launch_session_timeout_minutes": {
+							Description:  "Applicable only when session_type is APPLICATION or DESKTOP_AND_APPLICATION. Application pre-launch session timeout (in minutes). A pre-launch session is disconnected after the timeout. This is required if pre_launch_session_timeout_policy is set to AFTER.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      10,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"pre_launch_session_timeout_policy": {
+							Description:  "Applicable only when session_type is APPLICATION or DESKTOP_AND_APPLICATION. Application pre-launch session timeout policy. AFTER: Pre-launched session is disconnected after specified number of minutes. NEVER: Pre-launched session is never disconnected.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "AFTER",
+							ValidateFunc: validation.StringInSlice([]string{"AFTER", "NEVER"}, false),
+						},
+						"refresh_os_disk_after_logoff": {
+							Description:  "Applicable To: Dedicated instant clone desktop pools. Indicates whether and when to refresh the OS disks. NEVER: The OS disk is never refreshed. ALWAYS: The OS disk is refreshed every time the user logs off. EVERY: The OS disk is refreshed every refresh_period_days_for_replica_os_disk days. AT_SIZE: The OS disk is refreshed when its current size reaches refresh_threshold_percentage_for_replica_os_disk percent of its maximum allowable size.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "NEVER",
+							ValidateFunc: validation.StringInSlice([]string{"NEVER", "ALWAYS", "EVERY", "AT_SIZE"}, false),
+						},
+						"refresh_period_days_for_replica_os_disk": {
+							Description:  "Regular interval at which to refresh the OS disk. This is required if refresh_os_disk_after_logoff is set to EVERY.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"refresh_threshold_percentage_for_replica_os_disk": {
+							Description:  "Percentage of the maximum allowable size of the OS disk at which to refresh it. This is required if refresh_os_disk_after_logoff is set to AT_SIZE.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 100),
+						},
+						"session_timeout_policy": {
+							Description:  "Applicable only when session_type is APPLICATION or DESKTOP_AND_APPLICATION. DEFAULT: Application sessions will be disconnected either on reaching the global idle timeout or on reaching the max session timeout. NEVER: Application sessions will not be disconnected either on reaching the global idle timeout or on reaching the max session timeout.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "DEFAULT",
+							ValidateFunc: validation.StringInSlice([]string{"DEFAULT", "NEVER"}, false),
+						},
+					},
+				},
+			},
 			"session_type": {
 				Description:  "Supported session types for this desktop pool. If this property is set to APPLICATION then this desktop pool can be used for application pool creation. This will be useful when the machines in the pool support application remoting.",
 				Type:         schema.TypeString,
@@ -395,13 +582,44 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 				//ValidateFunc: validation.StringInSlice([]string{"START_MENU", "DESKTOP"}, false),
 			},
 			"source": {
-				Description:  "Source of the Machines in this Desktop Pool.",
+				Description:  "Source of the Machines in this Desktop Pool. INSTANT_CLONE: Instant clones of parent_vm_id and base_snapshot_id, or of im_stream_id and im_tag_id, customized with ClonePrep. VIRTUAL_CENTER: Full clones of vm_template_id, or of im_stream_id and im_tag_id, customized with Sysprep or not customized at all.",
 				Type:         schema.TypeString,
 				Required:     true,
 				ForceNew:     true,
 				ValidateFunc: validation.StringInSlice([]string{"INSTANT_CLONE", "VIRTUAL_CENTER"}, false),
 			},
-			// "specific_naming_settings": {},
+			"specific_naming_settings": {
+				Description: "Specified naming settings for Automated desktop pool. This is required when naming_method is SPECIFIED.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"specified_names": {
+							Description: "Names of the machines in the desktop pool. Adding a name provisions a new machine with that name and removing a name deletes the machine from the desktop pool and from disk.",
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"num_unassigned_machines_kept_powered_on": {
+							Description:  "Number of unassigned machines kept powered on. This value must be less than or equal to the number of specified_names.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"start_machines_in_maintenance_mode": {
+							Description: "Indicates whether to start machines in maintenance mode so that they can be customized before users are allowed to use them.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
 			"stop_provisioning_on_error": {
 				Description: "Disable provisioning on the pool if there is a provisioning error.",
 				Type:        schema.TypeBool,
@@ -427,7 +645,7 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 										Required:    true,
 									},
 									"sdrs_cluster": {
-										Description: "Id of the datastore.",
+										Description: "Applicable To: Full clone desktop pools. Indicates whether the datastore_id is a Storage DRS cluster rather than a datastore.",
 										Type:        schema.TypeBool,
 										Optional:    true,
 										Default:     false,
@@ -436,7 +654,7 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 							},
 						},
 						"reclaim_vm_disk_space": {
-							Description: "With vSphere 5.x, virtual machines can be configured to use a space efficient disk format that supports reclamation of unused diskspace (such as deleted files). This option reclaims unused diskspace on each virtual machine. The operation is initiated when an estimate of used disk space exceeds the specified threshold.",
+							Description: "Applicable To: Full clone desktop pools. With vSphere 5.x, virtual machines can be configured to use a space efficient disk format that supports reclamation of unused diskspace (such as deleted files). This option reclaims unused diskspace on each virtual machine. The operation is initiated when an estimate of used disk space exceeds the specified threshold.",
 							Type:        schema.TypeBool,
 							Optional:    true,
 						},
@@ -477,6 +695,7 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 				Description: "View Storage Accelerator settings for Managed desktop pool.",
 				Type:        schema.TypeList,
 				Optional:    true,
+				Computed:    true,
 				MaxItems:    1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -487,31 +706,36 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"days": {
-										Description: "List of days for a given range of time.",
+										Description: "List of days for a given range of time. Possible values are SUNDAY, MONDAY, TUESDAY, WEDNESDAY, THURSDAY, FRIDAY and SATURDAY.",
 										Type:        schema.TypeList,
 										Required:    true,
+										MinItems:    1,
 										Elem: &schema.Schema{
-											Type: schema.TypeString,
+											Type:         schema.TypeString,
+											ValidateFunc: validation.StringInSlice([]string{"SUNDAY", "MONDAY", "TUESDAY", "WEDNESDAY", "THURSDAY", "FRIDAY", "SATURDAY"}, false),
 										},
 									},
 									"end_time": {
-										Description: "Ending time for the blackout in 24-hour format.",
-										Type:        schema.TypeString,
-										Required:    true,
+										Description:  "Ending time for the blackout in 24-hour HH:MM format.",
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateBlackoutTime,
 									},
 									"start_time": {
-										Description: "Starting time for the blackout in 24-hour format.",
-										Type:        schema.TypeString,
-										Required:    true,
+										Description:  "Starting time for the blackout in 24-hour HH:MM format.",
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateBlackoutTime,
 									},
 								},
 							},
 						},
 						"regenerate_view_storage_accelerator_days": {
-							Description: "How often to regenerate the View Storage Accelerator cache. Measured in Days. This property is required if useViewStorageAccelerator is set to true. ",
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     7,
+							Description:  "How often to regenerate the View Storage Accelerator cache. Measured in Days. This property is required if use_view_storage_accelerator is set to true.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      7,
+							ValidateFunc: validation.IntAtLeast(1),
 						},
 						"use_view_storage_accelerator": {
 							Description: "Indicates whether to use View Storage Accelerator. ",
@@ -520,9 +744,33 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 							Default:     false,
 						},
 						"view_storage_accelerator_disk_types": {
-							Description: "Disk types to enable for the View Storage Accelerator feature. Not applicable to full clone pools",
+							Description: "Disk types enabled for the View Storage Accelerator feature. This is only applicable to linked clone desktop pools, so it is read from Horizon and can not be configured.",
 							Type:        schema.TypeString,
-							Optional:    true,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"wait_for_provisioning": {
+				Description: "Wait for the machines of the desktop pool to be provisioned after it is created or after an update that changes its naming, provisioning or image settings. The wait is limited by the create and update timeouts and is skipped while enable_provisioning is false. If provisioning is disabled because of stop_provisioning_on_error, the errors of the machines are returned.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"condition": {
+							Description:  "Condition to wait for. MACHINES_AVAILABLE: Wait for machine_count machines to be AVAILABLE. PROVISIONING_COMPLETE: Wait for all of the machines the naming settings call for to exist and to have finished provisioning and customization.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "MACHINES_AVAILABLE",
+							ValidateFunc: validation.StringInSlice([]string{"MACHINES_AVAILABLE", "PROVISIONING_COMPLETE"}, false),
+						},
+						"machine_count": {
+							Description:  "Number of AVAILABLE machines to wait for when condition is MACHINES_AVAILABLE.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
 						},
 					},
 				},
@@ -546,6 +794,73 @@ func resourceDesktopPoolAutomated() *schema.Resource {
 	}
 }
 
+// desktopPoolDisplayProtocolSettingsSchema is shared by the desktop pool resources that support display protocol settings.
+func desktopPoolDisplayProtocolSettingsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Display protocol settings.",
+		Type:        schema.TypeList,
+		Optional:    true,
+		Computed:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"allow_users_to_choose_protocol": {
+					Description: "Indicates whether the users can choose the protocol.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+				},
+				"default_display_protocol": {
+					Description:  "The default display protocol for the desktop pool.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "PCOIP",
+					ValidateFunc: validation.StringInSlice([]string{"RDP", "PCOIP", "BLAST"}, false),
+				},
+				"grid_vgpus_enabled": {
+					Description: "When 3D rendering is managed by the vSphere Client, this enables support for NVIDIA GRID vGPUs. This will be false if 3D rendering is not managed by the vSphere Client. If this is true, the host or cluster associated with the desktop pool must support NVIDIA GRID and vGPU types required by the desktop pool's VirtualMachines, VmTemplate or BaseImageSnapshot. If this is false, the desktop pool's VirtualMachines, VmTemplate or BaseImageSnapshot must not support NVIDIA GRID vGPUs. Since suspending VMs with passthrough devices such as vGPUs is not possible, power_policy cannot be set to SUSPEND if this is enabled. Default value is false. This can only be set when the desktop pool is created.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					ForceNew:    true,
+				},
+				"max_number_of_monitors": {
+					Description: "When render3D is disabled, the max_number_of_monitors and max_resolution_of_any_one_monitor settings determine the amount of vRAM assigned to machines in this desktop. The greater these values are, the more memory will be consume on the associated ESX hosts. Existing virtual machines must be powered off and subsequently powered on for the change to take effect. A restart will not cause the changes to take effect. If 3D is enabled and managed by View, the maximum number of monitors must be 1 or 2. For Instant Clones, this value is inherited from snapshot of Master VM. This property is required if renderer3D is set to AUTOMATIC, SOFTWARE, HARDWARE or DISABLED.",
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     2,
+				},
+				"max_resolution_of_any_one_monitor": {
+					Description:  "If 3D rendering is enabled and managed by View, this must be set to the default value. When 3D rendering is disabled, the max_number_of_monitors and max_resolution_of_any_one_monitor settings determine the amount of vRAM assigned to machines in this desktop. The greater these values are, the more memory will be consumed on the associated ESX hosts. This setting is only relevant on managed machines. Existing virtual machines must be powered off and subsequently powered on for the change to take effect. A restart will not cause the changes to take effect. For Instant Clones, this value is inherited from snapshot of Master VM.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "WUXGA",
+					ValidateFunc: validation.StringInSlice([]string{"WSXGA_PLUS", "WUXGA", "WQXGA", "UHD", "UHD_5K", "UHD_8K"}, false),
+				},
+				"renderer_3d": {
+					Description:  "3D rendering is supported on Windows 7 or later guests running on VMs with virtual hardware version 8 or later. The default_display_protocol must set to PCOIP and allow_users_to_choose_protocol must be set to false to enable 3D rendering. For instant clone source desktop 3D rendering always mapped to MANAGE_BY_VSPHERE_CLIENT.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "DISABLED",
+					ValidateFunc: validation.StringInSlice([]string{"MANAGE_BY_VSPHERE_CLIENT", "AUTOMATIC", "SOFTWARE", "HARDWARE", "DISABLED"}, false),
+				},
+				"session_collaboration_enabled": {
+					Description: "Enable session collaboration feature. Session collaboration allows a user to share their remote session with other users. BLAST must be configured as a supported protocol in supported_display_protocols.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+				},
+				"vram_size_mb": {
+					Description: "vRAM size for View managed 3D rendering. More VRAM can improve 3D performance. Size is in MB. On ESXi 5.0 hosts, the renderer allows a maximum VRAM size of 128MB. On ESXi 5.1 and later hosts, the maximum VRAM size is 512MB. For Instant Clones, this value is inherited from snapshot of Master VM. This property is required if renderer_3d is set to AUTOMATIC, SOFTWARE or HARDWARE.",
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     96,
+				},
+			},
+		},
+	}
+}
+
 func resourceDesktopPoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*apiClient).Client
 
@@ -559,6 +874,7 @@ func resourceDesktopPoolCreate(ctx context.Context, d *schema.ResourceData, meta
 	vCenterID := d.Get("vcenter_id").(string)
 	agID := d.Get("access_group_id").(string)
 	custType := d.Get("customization_type").(string)
+	sessionType := d.Get("session_type").(string)
 
 	//access_group_id
 
@@ -570,60 +886,53 @@ func resourceDesktopPoolCreate(ctx context.Context, d *schema.ResourceData, meta
 	body.NamingMethod = &namingMethod
 	body.VcenterId = &vCenterID
 	body.AccessGroupId = &agID
+	body.SessionType = &sessionType
 
-	autoAssign := false
 	if aa, ok := d.GetOk("automatic_user_assignment"); ok {
-		if userAssignment == "FLOATING" {
-			return diag.Errorf("automatic_user_assignment should not be set when user_assignment is \"FLOATING\"")
-		}
-		autoAssign = aa.(bool)
+		autoAssign := aa.(bool)
 		body.AutomaticUserAssignment = &autoAssign
 	}
 
-	multiAssign := false
 	if ma, ok := d.GetOk("allow_multiple_user_assignments"); ok {
-		if userAssignment == "FLOATING" {
-			return diag.Errorf("allow_multiple_user_assignments should not be set when user_assignment is \"FLOATING\"")
-		}
-		multiAssign = ma.(bool)
+		multiAssign := ma.(bool)
 		body.AllowMultipleUserAssignments = &multiAssign
 	}
 
-	if autoAssign && multiAssign {
-		return diag.Errorf("automatic_user_assignment and allow_multiple_user_assignments cannot both be true.")
-	}
-
 	if namingMethod == "PATTERN" {
-		if pns, ok := d.GetOk("pattern_naming_settings"); ok {
-			patternNamingRaw := pns.([]interface{})[0].(map[string]interface{})
-			namingPattern := patternNamingRaw["naming_pattern"].(string)
-			patternNaming := gohorizon.NewDesktopPoolVirtualMachinePatternNamingSettingsCreateSpec(namingPattern)
-			provTime := patternNamingRaw["provisioning_time"].(string)
-			patternNaming.ProvisioningTime = &provTime
-			maxMachine := int32(patternNamingRaw["max_number_of_machines"].(int))
-			patternNaming.MaxNumberOfMachines = &maxMachine
-
-			if patternNamingRaw["min_number_of_machines"].(int) > 0 {
-				if namingPattern == "UP_FRONT" {
-					return diag.Errorf("min_number_of_machines can not be set when naming_pattern is \"UP_FRONT\"")
-				}
-				minMachine := int32(patternNamingRaw["min_number_of_machines"].(int))
-				patternNaming.MinNumberOfMachines = &minMachine
-			}
+		patternNamingRaw := d.Get("pattern_naming_settings").([]interface{})[0].(map[string]interface{})
+		namingPattern := patternNamingRaw["naming_pattern"].(string)
+		patternNaming := gohorizon.NewDesktopPoolVirtualMachinePatternNamingSettingsCreateSpec(namingPattern)
+		provTime := patternNamingRaw["provisioning_time"].(string)
+		patternNaming.ProvisioningTime = &provTime
+		maxMachine := int32(patternNamingRaw["max_number_of_machines"].(int))
+		patternNaming.MaxNumberOfMachines = &maxMachine
 
-			if spare, ok := patternNamingRaw["number_of_spare_machines"]; ok {
-				if namingPattern == "UP_FRONT" {
-					return diag.Errorf("number_of_spare_machines can not be set when naming_pattern is \"UP_FRONT\"")
-				}
-				numSpare := int32(spare.(int))
-				patternNaming.NumberOfSpareMachines = &numSpare
-			}
+		if patternNamingRaw["min_number_of_machines"].(int) > 0 {
+			minMachine := int32(patternNamingRaw["min_number_of_machines"].(int))
+			patternNaming.MinNumberOfMachines = &minMachine
+		}
 
-			body.PatternNamingSettings = patternNaming
+		numSpare := int32(patternNamingRaw["number_of_spare_machines"].(int))
+		patternNaming.NumberOfSpareMachines = &numSpare
 
-		} else {
-			return diag.Errorf("pattern_naming_settings must be set if naming_method is \"PATTERN\"")
+		body.PatternNamingSettings = patternNaming
+	}
+
+	if namingMethod == "SPECIFIED" {
+		specificNamingRaw := d.Get("specific_naming_settings").([]interface{})[0].(map[string]interface{})
+		specifiedNames := []gohorizon.MachineSpecifiedName{}
+		for _, name := range expandStringSet(specificNamingRaw["specified_names"].(*schema.Set)) {
+			specifiedNames = append(specifiedNames, *gohorizon.NewMachineSpecifiedName(name))
 		}
+		numPoweredOn := int32(specificNamingRaw["num_unassigned_machines_kept_powered_on"].(int))
+		maintenanceMode := specificNamingRaw["start_machines_in_maintenance_mode"].(bool)
+
+		specificNaming := gohorizon.NewDesktopPoolVirtualMachineSpecifiedNamingSettingsCreateSpec()
+		specificNaming.SpecifiedNames = &specifiedNames
+		specificNaming.NumUnassignedMachinesKeptPoweredOn = &numPoweredOn
+		specificNaming.StartMachinesInMaintenanceMode = &maintenanceMode
+
+		body.SpecificNamingSettings = specificNaming
 	}
 
 	provSettingsRaw := d.Get("provisioning_settings").([]interface{})[0].(map[string]interface{})
@@ -639,121 +948,67 @@ func resourceDesktopPoolCreate(ctx context.Context, d *schema.ResourceData, meta
 		provSettings.DatacenterId = &dcID
 	}
 
-	switch source {
-	case "INSTANT_CLONE":
-		var icErr diag.Diagnostics
+	// the combination of image settings and source is validated by resourceDesktopPoolSourceCustomizeDiff
+	if imStreamID := provSettingsRaw["im_stream_id"].(string); imStreamID != "" {
+		imTagID := provSettingsRaw["im_tag_id"].(string)
+		provSettings.ImStreamId = &imStreamID
+		provSettings.ImTagId = &imTagID
+	} else if source == "INSTANT_CLONE" {
+		pvmid := provSettingsRaw["parent_vm_id"].(string)
+		basesnapID := provSettingsRaw["base_snapshot_id"].(string)
+		provSettings.ParentVmId = &pvmid
+		provSettings.BaseSnapshotId = &basesnapID
+	} else {
+		templateID := provSettingsRaw["vm_template_id"].(string)
+		provSettings.VmTemplateId = &templateID
+	}
 
-		if provSettingsRaw["parent_vm_id"].(string) != "" {
-			pvmid := provSettingsRaw["parent_vm_id"].(string)
-			provSettings.ParentVmId = &pvmid
-		} else {
-			icErr = append(icErr, diag.Errorf("parent_vm_id must be set when source is \"INSTANT_CLONE\"")...)
-		}
+	body.ProvisioningSettings = provSettings
 
-		if provSettingsRaw["base_snapshot_id"].(string) != "" {
-			basesnapID := provSettingsRaw["base_snapshot_id"].(string)
-			provSettings.BaseSnapshotId = &basesnapID
-		} else {
-			icErr = append(icErr, diag.Errorf("base_snapshot_id must be set when source is \"INSTANT_CLONE\"")...)
-		}
+	if nicsRaw, ok := d.GetOk("nics"); ok {
+		nics := expandDesktopPoolNicsCreateSpec(nicsRaw.([]interface{}))
+		body.Nics = &nics
+	}
 
-		if provSettingsRaw["vm_template_id"].(string) != "" {
-			icErr = append(icErr, diag.Errorf("vm_template_id must not be set when source is \"INSTANT_CLONE\"")...)
-		}
-
-		if icErr != nil {
-			return icErr
-		}
-	case "VIRTUAL_CENTER":
-		var fcErr diag.Diagnostics
-
-		if provSettingsRaw["vm_template_id"].(string) != "" {
-			templateID := provSettingsRaw["vm_template_id"].(string)
-			body.ProvisioningSettings.VmTemplateId = &templateID
-		} else {
-			fcErr = append(fcErr, diag.Errorf("vm_template_id must be set when source is \"VIRTUAL_CENTER\"")...)
-		}
-
-		if provSettingsRaw["parent_vm_id"].(string) != "" {
-			fcErr = append(fcErr, diag.Errorf("parent_vm_id must not be set when source is \"VIRTUAL_CENTER\"")...)
-		}
-
-		if provSettingsRaw["base_snapshot_id"].(string) != "" {
-			fcErr = append(fcErr, diag.Errorf("base_snapshot_id must not be set when source is \"VIRTUAL_CENTER\"")...)
-		}
-
-		if fcErr != nil {
-			return fcErr
-		}
-	default:
-		return diag.Errorf("invalid source - should not be possible to get here")
-	}
-
-	body.ProvisioningSettings = provSettings
-
-	clonePrepRaw, clonePrepDec := d.GetOk("clone_prep_settings")
-	sysPrepRaw, sysPrepDec := d.GetOk("sys_prep_settings")
 	custSettings := gohorizon.NewDesktopPoolCustomizationSettingsCreateSpec(custType)
-	var custErr diag.Diagnostics
+	doNotPowerOn := d.Get("do_not_power_on_vms_after_creation").(bool)
 	switch custType {
 	case "NONE":
-		if clonePrepDec {
-			custErr = append(custErr, diag.Errorf("clone_prep_settings must not be specified when customization_type is \"NONE\"")...)
-		}
-		if sysPrepDec {
-			custErr = append(custErr, diag.Errorf("sys_prep_settings must not be specified when customization_type is \"NONE\"")...)
-		}
+		custSettings.DoNotPowerOnVmsAfterCreation = &doNotPowerOn
 	case "SYS_PREP":
-		if clonePrepDec {
-			custErr = append(custErr, diag.Errorf("clone_prep_settings must not be specified when customization_type is \"SYS_PREP\"")...)
-		}
-		if sysPrepDec {
-			sysPrep := sysPrepRaw.([]interface{})[0].(map[string]interface{})
-			spCustID := sysPrep["sysprep_customization_spec_id"].(string)
-			custSettings.SysprepCustomizationSpecId = &spCustID
-		} else {
-			custErr = append(custErr, diag.Errorf("sys_prep_settings must be specified when customization_type is \"SYS_PREP\"")...)
-		}
+		sysPrep := d.Get("sys_prep_settings").([]interface{})[0].(map[string]interface{})
+		spCustID := sysPrep["sysprep_customization_spec_id"].(string)
+		custSettings.SysprepCustomizationSpecId = &spCustID
+		custSettings.DoNotPowerOnVmsAfterCreation = &doNotPowerOn
 	case "CLONE_PREP":
-		if sysPrepDec {
-			custErr = append(custErr, diag.Errorf("sys_prep_settings must not be specified when customization_type is \"CLONE_PREP\"")...)
-		}
-		if clonePrepDec {
-			clonePrep := clonePrepRaw.([]interface{})[0].(map[string]interface{})
-			clonePrepSettings := gohorizon.NewDesktopPoolCloneprepCustomizationSettingsCreateSpec()
-
-			adRDN := clonePrep["ad_container_rdn"].(string)
-			icdaID := clonePrep["instant_clone_domain_account_id"].(string)
-			pca := clonePrep["priming_computer_account"].(string)
-			psScriptName := clonePrep["post_synchronization_script_name"].(string)
-			psScriptParams := clonePrep["post_synchronization_script_parameters"].(string)
-			poScriptName := clonePrep["power_off_script_name"].(string)
-			poScriptParams := clonePrep["power_off_script_parameters"].(string)
-			reuse := clonePrep["reuse_pre_existing_accounts"].(bool)
-
-			clonePrepSettings.PostSynchronizationScriptName = &psScriptName
-			clonePrepSettings.PostSynchronizationScriptParameters = &psScriptParams
-			clonePrepSettings.PowerOffScriptName = &poScriptName
-			clonePrepSettings.PowerOffScriptParameters = &poScriptParams
-			clonePrepSettings.PrimingComputerAccount = &pca
-
-			custSettings.AdContainerRdn = &adRDN
-			custSettings.InstantCloneDomainAccountId = &icdaID
-			custSettings.ReusePreExistingAccounts = &reuse
-			custSettings.CloneprepCustomizationSettings = clonePrepSettings
-		} else {
-			custErr = append(custErr, diag.Errorf("clone_prep_settings must be specified when customization_type is \"CLONE_PREP\"")...)
-		}
+		clonePrep := d.Get("clone_prep_settings").([]interface{})[0].(map[string]interface{})
+		clonePrepSettings := gohorizon.NewDesktopPoolCloneprepCustomizationSettingsCreateSpec()
+
+		adRDN := clonePrep["ad_container_rdn"].(string)
+		icdaID := clonePrep["instant_clone_domain_account_id"].(string)
+		pca := clonePrep["priming_computer_account"].(string)
+		psScriptName := clonePrep["post_synchronization_script_name"].(string)
+		psScriptParams := clonePrep["post_synchronization_script_parameters"].(string)
+		poScriptName := clonePrep["power_off_script_name"].(string)
+		poScriptParams := clonePrep["power_off_script_parameters"].(string)
+		reuse := clonePrep["reuse_pre_existing_accounts"].(bool)
+
+		clonePrepSettings.PostSynchronizationScriptName = &psScriptName
+		clonePrepSettings.PostSynchronizationScriptParameters = &psScriptParams
+		clonePrepSettings.PowerOffScriptName = &poScriptName
+		clonePrepSettings.PowerOffScriptParameters = &poScriptParams
+		clonePrepSettings.PrimingComputerAccount = &pca
+
+		custSettings.AdContainerRdn = &adRDN
+		custSettings.InstantCloneDomainAccountId = &icdaID
+		custSettings.ReusePreExistingAccounts = &reuse
+		custSettings.CloneprepCustomizationSettings = clonePrepSettings
 	default:
 		return diag.Errorf("invalid customization_type - should not be possible to get here")
 	}
 
 	body.CustomizationSettings = custSettings
 
-	if custErr != nil {
-		return custErr
-	}
-
 	storSetRaw := d.Get("storage_settings").([]interface{})[0].(map[string]interface{})
 	datastoresRaw := storSetRaw["datastores"].(*schema.Set).List()
 	datastores := []gohorizon.DesktopPoolDatastoreSettingsCreateSpec{}
@@ -763,10 +1018,6 @@ func resourceDesktopPoolCreate(ctx context.Context, d *schema.ResourceData, meta
 		sdrs := rawds["sdrs_cluster"].(bool)
 		if source == "VIRTUAL_CENTER" {
 			datastore.SdrsCluster = &sdrs
-		} else {
-			if sdrs {
-				return diag.Errorf("sdrs_cluster cannot be configured for source type other than VIRTUAL_CENTER")
-			}
 		}
 		datastores = append(datastores, *datastore)
 	}
@@ -783,45 +1034,46 @@ func resourceDesktopPoolCreate(ctx context.Context, d *schema.ResourceData, meta
 	if rddID != "" {
 		storageSettings.ReplicaDiskDatastoreId = &rddID
 		storageSettings.UseSeparateDatastoresReplicaAndOsDisks = &separateds
-	} else {
-		if separateds {
-			return diag.Errorf("use_separate_datastores_replica_and_os_disks cannot be set if replica_disk_datastore_id is not specified")
-		}
 	}
 
 	if reclaim {
 		storageSettings.ReclaimVmDiskSpace = &reclaim
 		storageSettings.ReclamationThresholdMb = &reclaimThresh
-	} else {
-		if reclaimThresh > 0 {
-			return diag.Errorf("reclamation_threshold_mb should not be set if reclaim_vm_disk_space is false")
-		}
 	}
 
 	body.StorageSettings = storageSettings
 
+	if dps, ok := d.GetOk("display_protocol_settings"); ok {
+		body.DisplayProtocolSettings = expandDesktopPoolDisplayProtocolSettingsCreateSpec(dps.([]interface{}))
+	}
+
+	if vsas, ok := d.GetOk("view_storage_accelerator_settings"); ok {
+		body.ViewStorageAcceleratorSettings = expandDesktopPoolViewStorageAcceleratorSettingsCreateSpec(vsas.([]interface{}))
+	}
+
+	if ss, ok := d.GetOk("session_settings"); ok {
+		body.SessionSettings = expandDesktopPoolSessionSettingsCreateSpec(ss.([]interface{}), sessionType, userAssignment)
+	}
+
 	resp, err := client.InventoryApi.CreateDesktopPool(ctx).Body(*body).Execute()
 	if err != nil {
 		return returnResponseErr(resp, err)
 	}
 
-	// filter the pools by name to find the ID
-	// name is supposed to be unique across the environment
-	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"name\",\"value\":\"%s\"}", name)
-	pools, _, err := client.InventoryApi.ListDesktopPoolsV5(ctx).Filter(filter).Execute()
-	if err != nil {
-		return returnResponseErr(resp, err)
+	id, diags := findDesktopPoolIDByName(ctx, &client, name)
+	if diags != nil {
+		return diags
 	}
 
-	switch len(pools) {
-	case 0:
-		return diag.Errorf("could not find ID of pool that was created")
-	case 1:
-		d.SetId(*pools[0].Id)
-		return resourceDesktopPoolRead(ctx, d, meta)
-	default:
-		return diag.Errorf("Multiple pools found with same name - should not be possible")
+	d.SetId(id)
+
+	if _, ok := d.GetOk("wait_for_provisioning"); ok && enableProvisioning {
+		if diags := resourceDesktopPoolWaitForProvisioning(ctx, d, &client, d.Timeout(schema.TimeoutCreate)); diags != nil {
+			return diags
+		}
 	}
+
+	return resourceDesktopPoolRead(ctx, d, meta)
 }
 
 func resourceDesktopPoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -829,9 +1081,14 @@ func resourceDesktopPoolRead(ctx context.Context, d *schema.ResourceData, meta i
 
 	id := d.Id()
 
-	poolInfo, _, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+	poolInfo, resp, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the desktop pool was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	d.Set("access_group_id", poolInfo.AccessGroupId)
@@ -840,7 +1097,9 @@ func resourceDesktopPoolRead(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("category_folder_name", poolInfo.CategoryFolderName)
 	d.Set("cloud_assigned", poolInfo.CloudAssigned)
 	d.Set("cloud_managed", poolInfo.CloudManaged)
+	d.Set("cs_restriction_tags", poolInfo.CsRestrictionTags)
 	d.Set("delete_in_progress", poolInfo.DeleteInProgress)
+	d.Set("description", poolInfo.Description)
 	d.Set("display_assigned_machine_name", poolInfo.DisplayAssignedMachineName)
 	d.Set("display_machine_alias", poolInfo.DisplayMachineAlias)
 	d.Set("display_name", poolInfo.DisplayName)
@@ -855,29 +1114,1577 @@ func resourceDesktopPoolRead(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("source", poolInfo.Source)
 	d.Set("stop_provisioning_on_error", poolInfo.StopProvisioningOnError)
 	d.Set("transparent_page_sharing_scope", poolInfo.TransparentPageSharingScope)
-	d.Set("type", poolInfo.Type)
 	d.Set("user_assignment", poolInfo.UserAssignment)
 	d.Set("user_group_count", poolInfo.UserGroupCount)
 	d.Set("vcenter_id", poolInfo.VcenterId)
 
+	if poolInfo.CustomizationSettings != nil {
+		custSettings := poolInfo.CustomizationSettings
+		d.Set("customization_type", custSettings.CustomizationType)
+		d.Set("do_not_power_on_vms_after_creation", custSettings.GetDoNotPowerOnVmsAfterCreation())
+
+		switch custSettings.GetCustomizationType() {
+		case "CLONE_PREP":
+			if err := d.Set("clone_prep_settings", flattenDesktopPoolClonePrepSettings(custSettings)); err != nil {
+				return diag.FromErr(err)
+			}
+			d.Set("sys_prep_settings", nil)
+		case "SYS_PREP":
+			if err := d.Set("sys_prep_settings", flattenDesktopPoolSysPrepSettings(custSettings)); err != nil {
+				return diag.FromErr(err)
+			}
+			d.Set("clone_prep_settings", nil)
+		default:
+			d.Set("clone_prep_settings", nil)
+			d.Set("sys_prep_settings", nil)
+		}
+	}
+
+	if poolInfo.DisplayProtocolSettings != nil {
+		if err := d.Set("display_protocol_settings", flattenDesktopPoolDisplayProtocolSettings(poolInfo.DisplayProtocolSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set("nics", flattenDesktopPoolNics(poolInfo.GetNics())); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if poolInfo.PatternNamingSettings != nil {
+		if err := d.Set("pattern_naming_settings", flattenDesktopPoolPatternNamingSettings(poolInfo.PatternNamingSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		d.Set("pattern_naming_settings", nil)
+	}
+
+	if poolInfo.SpecificNamingSettings != nil {
+		machines, diags := listDesktopPoolMachines(ctx, &client, id)
+		if diags != nil {
+			return diags
+		}
+
+		if err := d.Set("specific_naming_settings", flattenDesktopPoolSpecificNamingSettings(poolInfo.SpecificNamingSettings, machines)); err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		d.Set("specific_naming_settings", nil)
+	}
+
+	if poolInfo.ProvisioningSettings != nil {
+		if err := d.Set("provisioning_settings", flattenDesktopPoolProvisioningSettings(poolInfo.ProvisioningSettings, poolInfo.ProvisioningStatusData)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if poolInfo.SessionSettings != nil {
+		if err := d.Set("session_settings", flattenDesktopPoolSessionSettings(poolInfo.SessionSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if poolInfo.StorageSettings != nil {
+		if err := d.Set("storage_settings", flattenDesktopPoolStorageSettings(poolInfo.StorageSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if poolInfo.ViewStorageAcceleratorSettings != nil {
+		if err := d.Set("view_storage_accelerator_settings", flattenDesktopPoolViewStorageAcceleratorSettings(poolInfo.ViewStorageAcceleratorSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return nil
 }
 
-func resourceDesktopPoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// use the meta value to retrieve your client from the provider configure method
-	// client := meta.(*apiClient)
+func resourceDesktopPoolDisplayProtocolCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// the block is computed, so only validate it when it is actually in the configuration
+	if !blockConfigured(d.GetRawConfig(), "display_protocol_settings") {
+		return nil
+	}
+	dps, ok := d.GetOk("display_protocol_settings")
+	if !ok {
+		return nil
+	}
+	dpSettingsRaw := dps.([]interface{})[0].(map[string]interface{})
+	allowChoose := dpSettingsRaw["allow_users_to_choose_protocol"].(bool)
+	defaultProtocol := dpSettingsRaw["default_display_protocol"].(string)
+	gridVGPUs := dpSettingsRaw["grid_vgpus_enabled"].(bool)
+	maxMonitors := dpSettingsRaw["max_number_of_monitors"].(int)
+	maxResolution := dpSettingsRaw["max_resolution_of_any_one_monitor"].(string)
+	renderer3D := dpSettingsRaw["renderer_3d"].(string)
+	collaboration := dpSettingsRaw["session_collaboration_enabled"].(bool)
+
+	if renderer3D != "DISABLED" {
+		if defaultProtocol != "PCOIP" {
+			return fmt.Errorf("default_display_protocol must be \"PCOIP\" when renderer_3d is \"%s\"", renderer3D)
+		}
+		if allowChoose {
+			return fmt.Errorf("allow_users_to_choose_protocol must be false when renderer_3d is \"%s\"", renderer3D)
+		}
+	}
+
+	if gridVGPUs && renderer3D != "MANAGE_BY_VSPHERE_CLIENT" {
+		return fmt.Errorf("grid_vgpus_enabled can only be true when renderer_3d is \"MANAGE_BY_VSPHERE_CLIENT\"")
+	}
 
-	return diag.Errorf("not implemented")
+	switch renderer3D {
+	case "AUTOMATIC", "SOFTWARE", "HARDWARE":
+		if maxMonitors < 1 || maxMonitors > 2 {
+			return fmt.Errorf("max_number_of_monitors must be 1 or 2 when renderer_3d is \"%s\"", renderer3D)
+		}
+		if maxResolution != "WUXGA" {
+			return fmt.Errorf("max_resolution_of_any_one_monitor must be \"WUXGA\" when renderer_3d is \"%s\"", renderer3D)
+		}
+	}
+
+	if collaboration && !allowChoose && defaultProtocol != "BLAST" {
+		return fmt.Errorf("session_collaboration_enabled requires BLAST, so default_display_protocol must be \"BLAST\" or allow_users_to_choose_protocol must be true")
+	}
+
+	return nil
 }
 
-func resourceDesktopPoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient).Client
+func resourceDesktopPoolUserAssignmentCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// automatic_user_assignment is computed, so only the configured values are validated
+	config := d.GetRawConfig()
+	autoAssign := configBool(config, "automatic_user_assignment")
+	multiAssign := configBool(config, "allow_multiple_user_assignments")
 
-	id := d.Id()
+	if d.Get("user_assignment").(string) == "FLOATING" {
+		if autoAssign {
+			return fmt.Errorf("automatic_user_assignment should not be set when user_assignment is \"FLOATING\"")
+		}
+		if multiAssign {
+			return fmt.Errorf("allow_multiple_user_assignments should not be set when user_assignment is \"FLOATING\"")
+		}
+	}
 
-	_, err := client.InventoryApi.DeleteDesktopPool(ctx, id).Execute()
-	if err != nil {
-		return diag.FromErr(err)
+	if autoAssign && multiAssign {
+		return fmt.Errorf("automatic_user_assignment and allow_multiple_user_assignments cannot both be true")
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolNamingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	_, patternNaming := d.GetOk("pattern_naming_settings")
+	_, specificNaming := d.GetOk("specific_naming_settings")
+
+	switch d.Get("naming_method").(string) {
+	case "PATTERN":
+		if !patternNaming {
+			return fmt.Errorf("pattern_naming_settings must be set if naming_method is \"PATTERN\"")
+		}
+		if specificNaming {
+			return fmt.Errorf("specific_naming_settings must not be set if naming_method is \"PATTERN\"")
+		}
+
+		maxMachines := d.Get("pattern_naming_settings.0.max_number_of_machines").(int)
+		numSpare := d.Get("pattern_naming_settings.0.number_of_spare_machines").(int)
+		if numSpare > maxMachines {
+			return fmt.Errorf("number_of_spare_machines can not be greater than max_number_of_machines")
+		}
+
+		// min_number_of_machines is computed, so only the configured value is validated
+		if !blockConfigured(d.GetRawConfig(), "pattern_naming_settings") {
+			return nil
+		}
+		minMachinesRaw := d.GetRawConfig().GetAttr("pattern_naming_settings").Index(cty.NumberIntVal(0)).GetAttr("min_number_of_machines")
+		if minMachinesRaw.IsNull() || !minMachinesRaw.IsKnown() {
+			return nil
+		}
+		minMachines := d.Get("pattern_naming_settings.0.min_number_of_machines").(int)
+		if minMachines > 0 && d.Get("pattern_naming_settings.0.provisioning_time").(string) == "UP_FRONT" {
+			return fmt.Errorf("min_number_of_machines can not be set when provisioning_time is \"UP_FRONT\"")
+		}
+		if minMachines > maxMachines {
+			return fmt.Errorf("min_number_of_machines can not be greater than max_number_of_machines")
+		}
+	case "SPECIFIED":
+		if !specificNaming {
+			return fmt.Errorf("specific_naming_settings must be set if naming_method is \"SPECIFIED\"")
+		}
+		if patternNaming {
+			return fmt.Errorf("pattern_naming_settings must not be set if naming_method is \"SPECIFIED\"")
+		}
+
+		if !d.NewValueKnown("specific_naming_settings.0.specified_names") {
+			return nil
+		}
+		numPoweredOn := d.Get("specific_naming_settings.0.num_unassigned_machines_kept_powered_on").(int)
+		if numPoweredOn > d.Get("specific_naming_settings.0.specified_names").(*schema.Set).Len() {
+			return fmt.Errorf("num_unassigned_machines_kept_powered_on can not be greater than the number of specified_names")
+		}
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolSourceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	source := d.Get("source").(string)
+	custType := d.Get("customization_type").(string)
+
+	imStream := diffValueSet(d, "provisioning_settings.0.im_stream_id")
+	imTag := diffValueSet(d, "provisioning_settings.0.im_tag_id")
+	parentVM := diffValueSet(d, "provisioning_settings.0.parent_vm_id")
+	baseSnapshot := diffValueSet(d, "provisioning_settings.0.base_snapshot_id")
+	vmTemplate := diffValueSet(d, "provisioning_settings.0.vm_template_id")
+
+	if imStream && !imTag {
+		return fmt.Errorf("im_tag_id must be set when im_stream_id is set")
+	}
+	if !imStream && imTag {
+		return fmt.Errorf("im_tag_id can only be set when im_stream_id is set")
+	}
+
+	switch source {
+	case "INSTANT_CLONE":
+		if imStream && (parentVM || baseSnapshot) {
+			return fmt.Errorf("parent_vm_id and base_snapshot_id must not be set when im_stream_id is set")
+		}
+		if !imStream && (!parentVM || !baseSnapshot) {
+			return fmt.Errorf("parent_vm_id and base_snapshot_id must be set when source is \"INSTANT_CLONE\" and im_stream_id is not set")
+		}
+		if vmTemplate {
+			return fmt.Errorf("vm_template_id must not be set when source is \"INSTANT_CLONE\"")
+		}
+		if custType != "CLONE_PREP" {
+			return fmt.Errorf("customization_type must be \"CLONE_PREP\" when source is \"INSTANT_CLONE\"")
+		}
+		if _, ok := d.GetOk("delete_settings"); ok && !d.Get("delete_settings.0.delete_from_disk").(bool) {
+			return fmt.Errorf("delete_settings.0.delete_from_disk must be true when source is \"INSTANT_CLONE\"")
+		}
+	case "VIRTUAL_CENTER":
+		if imStream && vmTemplate {
+			return fmt.Errorf("vm_template_id must not be set when im_stream_id is set")
+		}
+		if !imStream && !vmTemplate {
+			return fmt.Errorf("vm_template_id must be set when source is \"VIRTUAL_CENTER\" and im_stream_id is not set")
+		}
+		if parentVM || baseSnapshot {
+			return fmt.Errorf("parent_vm_id and base_snapshot_id must not be set when source is \"VIRTUAL_CENTER\"")
+		}
+		if custType == "CLONE_PREP" {
+			return fmt.Errorf("customization_type must be \"NONE\" or \"SYS_PREP\" when source is \"VIRTUAL_CENTER\"")
+		}
+		if _, ok := d.GetOk("nics"); ok {
+			return fmt.Errorf("nics can only be set when source is \"INSTANT_CLONE\"")
+		}
+		if _, ok := d.GetOk("push_image_settings"); ok {
+			return fmt.Errorf("push_image_settings can only be set when source is \"INSTANT_CLONE\"")
+		}
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolCustomizationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	custType := d.Get("customization_type").(string)
+	_, clonePrep := d.GetOk("clone_prep_settings")
+	_, sysPrep := d.GetOk("sys_prep_settings")
+
+	switch custType {
+	case "NONE":
+		if clonePrep {
+			return fmt.Errorf("clone_prep_settings must not be specified when customization_type is \"NONE\"")
+		}
+		if sysPrep {
+			return fmt.Errorf("sys_prep_settings must not be specified when customization_type is \"NONE\"")
+		}
+	case "SYS_PREP":
+		if clonePrep {
+			return fmt.Errorf("clone_prep_settings must not be specified when customization_type is \"SYS_PREP\"")
+		}
+		if !sysPrep {
+			return fmt.Errorf("sys_prep_settings must be specified when customization_type is \"SYS_PREP\"")
+		}
+	case "CLONE_PREP":
+		if sysPrep {
+			return fmt.Errorf("sys_prep_settings must not be specified when customization_type is \"CLONE_PREP\"")
+		}
+		if !clonePrep {
+			return fmt.Errorf("clone_prep_settings must be specified when customization_type is \"CLONE_PREP\"")
+		}
+		if d.Get("do_not_power_on_vms_after_creation").(bool) {
+			return fmt.Errorf("do_not_power_on_vms_after_creation can not be set when customization_type is \"CLONE_PREP\"")
+		}
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolStorageCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	source := d.Get("source").(string)
+
+	if source != "VIRTUAL_CENTER" {
+		for _, raw := range d.Get("storage_settings.0.datastores").(*schema.Set).List() {
+			if raw.(map[string]interface{})["sdrs_cluster"].(bool) {
+				return fmt.Errorf("sdrs_cluster cannot be configured for source type other than VIRTUAL_CENTER")
+			}
+		}
+	}
+
+	replicaDatastore := diffValueSet(d, "storage_settings.0.replica_disk_datastore_id")
+	separateDatastores := d.Get("storage_settings.0.use_separate_datastores_replica_and_os_disks").(bool)
+	if separateDatastores && !replicaDatastore {
+		return fmt.Errorf("use_separate_datastores_replica_and_os_disks cannot be set if replica_disk_datastore_id is not specified")
+	}
+	if replicaDatastore && !separateDatastores {
+		return fmt.Errorf("replica_disk_datastore_id can only be set when use_separate_datastores_replica_and_os_disks is true")
+	}
+	if replicaDatastore && source != "INSTANT_CLONE" {
+		return fmt.Errorf("replica_disk_datastore_id can only be set when source is \"INSTANT_CLONE\"")
+	}
+
+	if d.Get("storage_settings.0.reclaim_vm_disk_space").(bool) {
+		if source != "VIRTUAL_CENTER" {
+			return fmt.Errorf("reclaim_vm_disk_space cannot be configured for source type other than VIRTUAL_CENTER")
+		}
+	} else if d.Get("storage_settings.0.reclamation_threshold_mb").(int) > 0 {
+		return fmt.Errorf("reclamation_threshold_mb should not be set if reclaim_vm_disk_space is false")
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolNicsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for i, nicRaw := range d.Get("nics").([]interface{}) {
+		for j, labelRaw := range nicRaw.(map[string]interface{})["network_label_assignment_specs"].([]interface{}) {
+			labelMap := labelRaw.(map[string]interface{})
+			labelName := labelMap["network_label_name"].(string)
+			maxLabel := labelMap["max_label"].(int)
+
+			// a max_label that is not known until apply reads as 0, so it is only required once it is known
+			maxLabelKnown := d.NewValueKnown(fmt.Sprintf("nics.%d.network_label_assignment_specs.%d.max_label", i, j))
+
+			if labelMap["max_label_type"].(string) == "LIMITED" {
+				if maxLabel == 0 && maxLabelKnown {
+					return fmt.Errorf("max_label must be set for network label %s when max_label_type is \"LIMITED\"", labelName)
+				}
+			} else if maxLabel > 0 {
+				return fmt.Errorf("max_label can not be set for network label %s when max_label_type is \"UNLIMITED\"", labelName)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolSessionSettingsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// the block is computed, so only validate it when it is actually in the configuration
+	if !blockConfigured(d.GetRawConfig(), "session_settings") {
+		return nil
+	}
+	if _, ok := d.GetOk("session_settings"); !ok {
+		return nil
+	}
+
+	// values that are not known until apply are treated as set
+	intSet := func(key string) bool {
+		return !d.NewValueKnown(key) || d.Get(key).(int) != 0
+	}
+
+	if d.Get("session_settings.0.disconnected_session_timeout_policy").(string) == "AFTER" && !intSet("session_settings.0.disconnected_session_timeout_minutes") {
+		return fmt.Errorf("disconnected_session_timeout_minutes must be set when disconnected_session_timeout_policy is \"AFTER\"")
+	}
+
+	if d.Get("user_assignment").(string) == "FLOATING" {
+		return nil
+	}
+
+	if d.Get("session_settings.0.allow_multiple_sessions_per_user").(bool) {
+		return fmt.Errorf("allow_multiple_sessions_per_user can only be set when user_assignment is \"FLOATING\"")
+	}
+	if d.Get("session_settings.0.delete_or_refresh_machine_after_logoff").(string) != "NEVER" {
+		return fmt.Errorf("delete_or_refresh_machine_after_logoff can only be set when user_assignment is \"FLOATING\"")
+	}
+
+	switch d.Get("session_settings.0.refresh_os_disk_after_logoff").(string) {
+	case "EVERY":
+		if !intSet("session_settings.0.refresh_period_days_for_replica_os_disk") {
+			return fmt.Errorf("refresh_period_days_for_replica_os_disk must be set when refresh_os_disk_after_logoff is \"EVERY\"")
+		}
+	case "AT_SIZE":
+		if !intSet("session_settings.0.refresh_threshold_percentage_for_replica_os_disk") {
+			return fmt.Errorf("refresh_threshold_percentage_for_replica_os_disk must be set when refresh_os_disk_after_logoff is \"AT_SIZE\"")
+		}
+	}
+
+	return nil
+}
+
+func expandDesktopPoolDisplayProtocolSettingsCreateSpec(raw []interface{}) *gohorizon.DesktopPoolDisplayProtocolSettingsCreateSpec {
+	dpSettingsRaw := raw[0].(map[string]interface{})
+	allowChoose := dpSettingsRaw["allow_users_to_choose_protocol"].(bool)
+	defaultProtocol := dpSettingsRaw["default_display_protocol"].(string)
+	gridVGPUs := dpSettingsRaw["grid_vgpus_enabled"].(bool)
+	maxMonitors := int32(dpSettingsRaw["max_number_of_monitors"].(int))
+	maxResolution := dpSettingsRaw["max_resolution_of_any_one_monitor"].(string)
+	renderer3D := dpSettingsRaw["renderer_3d"].(string)
+	collaboration := dpSettingsRaw["session_collaboration_enabled"].(bool)
+	vram := int32(dpSettingsRaw["vram_size_mb"].(int))
+
+	dpSettings := gohorizon.NewDesktopPoolDisplayProtocolSettingsCreateSpec()
+	dpSettings.AllowUsersToChooseProtocol = &allowChoose
+	dpSettings.DefaultDisplayProtocol = &defaultProtocol
+	dpSettings.GridVgpusEnabled = &gridVGPUs
+	dpSettings.MaxNumberOfMonitors = &maxMonitors
+	dpSettings.MaxResolutionOfAnyOneMonitor = &maxResolution
+	dpSettings.Renderer3d = &renderer3D
+	dpSettings.SessionCollaborationEnabled = &collaboration
+	dpSettings.VramSizeMb = &vram
+
+	return dpSettings
+}
+
+func expandDesktopPoolDisplayProtocolSettingsUpdateSpec(raw []interface{}) *gohorizon.DesktopPoolDisplayProtocolSettingsUpdateSpec {
+	dpSettingsRaw := raw[0].(map[string]interface{})
+	allowChoose := dpSettingsRaw["allow_users_to_choose_protocol"].(bool)
+	defaultProtocol := dpSettingsRaw["default_display_protocol"].(string)
+	maxMonitors := int32(dpSettingsRaw["max_number_of_monitors"].(int))
+	maxResolution := dpSettingsRaw["max_resolution_of_any_one_monitor"].(string)
+	renderer3D := dpSettingsRaw["renderer_3d"].(string)
+	collaboration := dpSettingsRaw["session_collaboration_enabled"].(bool)
+	vram := int32(dpSettingsRaw["vram_size_mb"].(int))
+
+	dpSettings := gohorizon.NewDesktopPoolDisplayProtocolSettingsUpdateSpec(allowChoose, defaultProtocol)
+	dpSettings.MaxNumberOfMonitors = &maxMonitors
+	dpSettings.MaxResolutionOfAnyOneMonitor = &maxResolution
+	dpSettings.Renderer3d = &renderer3D
+	dpSettings.SessionCollaborationEnabled = &collaboration
+	dpSettings.VramSizeMb = &vram
+
+	return dpSettings
+}
+
+func expandDesktopPoolNicsCreateSpec(raw []interface{}) []gohorizon.DesktopPoolNetworkInterfaceCardSettingsCreateSpec {
+	nics := []gohorizon.DesktopPoolNetworkInterfaceCardSettingsCreateSpec{}
+	for _, nicRaw := range raw {
+		nicMap := nicRaw.(map[string]interface{})
+		nic := gohorizon.NewDesktopPoolNetworkInterfaceCardSettingsCreateSpec(nicMap["network_interface_card_id"].(string))
+
+		labelSpecs := []gohorizon.NetworkLabelAssignmentSettingsCreateSpec{}
+		for _, labelRaw := range nicMap["network_label_assignment_specs"].([]interface{}) {
+			labelMap := labelRaw.(map[string]interface{})
+			enabled := labelMap["enabled"].(bool)
+			maxLabelType := labelMap["max_label_type"].(string)
+			labelName := labelMap["network_label_name"].(string)
+
+			labelSpec := gohorizon.NewNetworkLabelAssignmentSettingsCreateSpec(maxLabelType, labelName)
+			labelSpec.Enabled = &enabled
+
+			labelSpec.MaxLabel = expandDesktopPoolNetworkLabelMaxLabel(labelMap)
+
+			labelSpecs = append(labelSpecs, *labelSpec)
+		}
+		nic.NetworkLabelAssignmentSpecs = &labelSpecs
+
+		nics = append(nics, *nic)
+	}
+
+	return nics
+}
+
+func expandDesktopPoolNicsUpdateSpec(raw []interface{}) []gohorizon.DesktopPoolNetworkInterfaceCardSettingsUpdateSpec {
+	nics := []gohorizon.DesktopPoolNetworkInterfaceCardSettingsUpdateSpec{}
+	for _, nicRaw := range raw {
+		nicMap := nicRaw.(map[string]interface{})
+		nic := gohorizon.NewDesktopPoolNetworkInterfaceCardSettingsUpdateSpec(nicMap["network_interface_card_id"].(string))
+
+		labelSpecs := []gohorizon.NetworkLabelAssignmentSettingsUpdateSpec{}
+		for _, labelRaw := range nicMap["network_label_assignment_specs"].([]interface{}) {
+			labelMap := labelRaw.(map[string]interface{})
+			enabled := labelMap["enabled"].(bool)
+			maxLabelType := labelMap["max_label_type"].(string)
+			labelName := labelMap["network_label_name"].(string)
+
+			labelSpec := gohorizon.NewNetworkLabelAssignmentSettingsUpdateSpec(enabled, maxLabelType, labelName)
+
+			labelSpec.MaxLabel = expandDesktopPoolNetworkLabelMaxLabel(labelMap)
+
+			labelSpecs = append(labelSpecs, *labelSpec)
+		}
+		nic.NetworkLabelAssignmentSpecs = &labelSpecs
+
+		nics = append(nics, *nic)
+	}
+
+	return nics
+}
+
+// expandDesktopPoolNetworkLabelMaxLabel returns max_label of a network label assignment spec, which is only sent for LIMITED specs.
+func expandDesktopPoolNetworkLabelMaxLabel(labelMap map[string]interface{}) *int32 {
+	if labelMap["max_label_type"].(string) != "LIMITED" {
+		return nil
+	}
+
+	maxLabel := int32(labelMap["max_label"].(int))
+
+	return &maxLabel
+}
+
+func flattenDesktopPoolNics(nicSettings []gohorizon.DesktopPoolNetworkInterfaceCardSettings) []interface{} {
+	nics := []interface{}{}
+	for _, nicSetting := range nicSettings {
+		labelSpecs := []interface{}{}
+		for _, labelSpec := range nicSetting.GetNetworkLabelAssignmentSpecs() {
+			labelSpecs = append(labelSpecs, map[string]interface{}{
+				"enabled":            labelSpec.GetEnabled(),
+				"max_label":          labelSpec.GetMaxLabel(),
+				"max_label_type":     labelSpec.GetMaxLabelType(),
+				"network_label_name": labelSpec.GetNetworkLabelName(),
+			})
+		}
+
+		nics = append(nics, map[string]interface{}{
+			"network_interface_card_id":      nicSetting.GetNetworkInterfaceCardId(),
+			"network_label_assignment_specs": labelSpecs,
+		})
+	}
+
+	return nics
+}
+
+// desktopPoolSessionSettings holds the session settings that apply to a desktop pool, which the create and update specs are built from.
+type desktopPoolSessionSettings struct {
+	allowMultipleSessions bool
+	allowReset            bool
+	deleteOrRefresh       *string
+	disconnectedMinutes   *int32
+	disconnectedPolicy    string
+	emptyMinutes          *int32
+	emptyPolicy           *string
+	logoff                *bool
+	powerPolicy           *string
+	preLaunchMinutes      *int32
+	preLaunchPolicy       *string
+	refreshOSDisk         *string
+	refreshPeriodDays     *int32
+	refreshThreshold      *int32
+	sessionTimeoutPolicy  *string
+}
+
+// expandDesktopPoolSessionSettings only keeps the settings of the session_settings block that are
+// applicable to the session type and user assignment of the desktop pool.
+func expandDesktopPoolSessionSettings(raw []interface{}, sessionType, userAssignment string) *desktopPoolSessionSettings {
+	ssRaw := raw[0].(map[string]interface{})
+	settings := &desktopPoolSessionSettings{
+		allowReset:         ssRaw["allow_users_to_reset_machines"].(bool),
+		disconnectedPolicy: ssRaw["disconnected_session_timeout_policy"].(string),
+	}
+
+	if settings.disconnectedPolicy == "AFTER" {
+		disconnectedMinutes := int32(ssRaw["disconnected_session_timeout_minutes"].(int))
+		settings.disconnectedMinutes = &disconnectedMinutes
+	}
+
+	if powerPolicy := ssRaw["power_policy"].(string); powerPolicy != "" {
+		settings.powerPolicy = &powerPolicy
+	}
+
+	if userAssignment == "FLOATING" {
+		settings.allowMultipleSessions = ssRaw["allow_multiple_sessions_per_user"].(bool)
+		deleteOrRefresh := ssRaw["delete_or_refresh_machine_after_logoff"].(string)
+		settings.deleteOrRefresh = &deleteOrRefresh
+	} else {
+		refreshOSDisk := ssRaw["refresh_os_disk_after_logoff"].(string)
+		settings.refreshOSDisk = &refreshOSDisk
+		switch refreshOSDisk {
+		case "EVERY":
+			refreshPeriodDays := int32(ssRaw["refresh_period_days_for_replica_os_disk"].(int))
+			settings.refreshPeriodDays = &refreshPeriodDays
+		case "AT_SIZE":
+			refreshThreshold := int32(ssRaw["refresh_threshold_percentage_for_replica_os_disk"].(int))
+			settings.refreshThreshold = &refreshThreshold
+		}
+	}
+
+	// the application session settings are rejected for pools that only provide desktops
+	if sessionType != "DESKTOP" {
+		emptyPolicy := ssRaw["empty_session_timeout_policy"].(string)
+		logoff := ssRaw["logoff_after_timeout"].(bool)
+		preLaunchPolicy := ssRaw["pre_launch_session_timeout_policy"].(string)
+		sessionTimeoutPolicy := ssRaw["session_timeout_policy"].(string)
+		settings.emptyPolicy = &emptyPolicy
+		settings.logoff = &logoff
+		settings.preLaunchPolicy = &preLaunchPolicy
+		settings.sessionTimeoutPolicy = &sessionTimeoutPolicy
+
+		if emptyPolicy == "AFTER" {
+			emptyMinutes := int32(ssRaw["empty_session_timeout_minutes"].(int))
+			settings.emptyMinutes = &emptyMinutes
+		}
+
+		if preLaunchPolicy == "AFTER" {
+			preLaunchMinutes := int32(ssRaw["pre_launch_session_timeout_minutes"].(int))
+			settings.preLaunchMinutes = &preLaunchMinutes
+		}
+	}
+
+	return settings
+}
+
+func expandDesktopPoolSessionSettingsCreateSpec(raw []interface{}, sessionType, userAssignment string) *gohorizon.DesktopPoolSessionSettingsCreateSpec {
+	settings := expandDesktopPoolSessionSettings(raw, sessionType, userAssignment)
+
+	sessionSettings := gohorizon.NewDesktopPoolSessionSettingsCreateSpec()
+	sessionSettings.AllowMultipleSessionsPerUser = &settings.allowMultipleSessions
+	sessionSettings.AllowUsersToResetMachines = &settings.allowReset
+	sessionSettings.DeleteOrRefreshMachineAfterLogoff = settings.deleteOrRefresh
+	sessionSettings.DisconnectedSessionTimeoutMinutes = settings.disconnectedMinutes
+	sessionSettings.DisconnectedSessionTimeoutPolicy = &settings.disconnectedPolicy
+	sessionSettings.EmptySessionTimeoutMinutes = settings.emptyMinutes
+	sessionSettings.EmptySessionTimeoutPolicy = settings.emptyPolicy
+	sessionSettings.LogoffAfterTimeout = settings.logoff
+	sessionSettings.PowerPolicy = settings.powerPolicy
+	sessionSettings.PreLaunchSessionTimeoutMinutes = settings.preLaunchMinutes
+	sessionSettings.PreLaunchSessionTimeoutPolicy = settings.preLaunchPolicy
+	sessionSettings.RefreshOsDiskAfterLogoff = settings.refreshOSDisk
+	sessionSettings.RefreshPeriodDaysForReplicaOsDisk = settings.refreshPeriodDays
+	sessionSettings.RefreshThresholdPercentageForReplicaOsDisk = settings.refreshThreshold
+	sessionSettings.SessionTimeoutPolicy = settings.sessionTimeoutPolicy
+
+	return sessionSettings
+}
+
+func expandDesktopPoolSessionSettingsUpdateSpec(raw []interface{}, sessionType, userAssignment string) *gohorizon.DesktopPoolSessionSettingsUpdateSpec {
+	settings := expandDesktopPoolSessionSettings(raw, sessionType, userAssignment)
+
+	sessionSettings := gohorizon.NewDesktopPoolSessionSettingsUpdateSpec(settings.allowMultipleSessions, settings.disconnectedPolicy)
+	sessionSettings.AllowUsersToResetMachines = &settings.allowReset
+	sessionSettings.DeleteOrRefreshMachineAfterLogoff = settings.deleteOrRefresh
+	sessionSettings.DisconnectedSessionTimeoutMinutes = settings.disconnectedMinutes
+	sessionSettings.EmptySessionTimeoutMinutes = settings.emptyMinutes
+	sessionSettings.EmptySessionTimeoutPolicy = settings.emptyPolicy
+	sessionSettings.LogoffAfterTimeout = settings.logoff
+	sessionSettings.PowerPolicy = settings.powerPolicy
+	sessionSettings.PreLaunchSessionTimeoutMinutes = settings.preLaunchMinutes
+	sessionSettings.PreLaunchSessionTimeoutPolicy = settings.preLaunchPolicy
+	sessionSettings.RefreshOsDiskAfterLogoff = settings.refreshOSDisk
+	sessionSettings.RefreshPeriodDaysForReplicaOsDisk = settings.refreshPeriodDays
+	sessionSettings.RefreshThresholdPercentageForReplicaOsDisk = settings.refreshThreshold
+	sessionSettings.SessionTimeoutPolicy = settings.sessionTimeoutPolicy
+
+	return sessionSettings
+}
+
+func flattenDesktopPoolSessionSettings(ssSettings *gohorizon.DesktopPoolSessionSettingsV3) []interface{} {
+	session := map[string]interface{}{
+		"allow_multiple_sessions_per_user":                 ssSettings.GetAllowMultipleSessionsPerUser(),
+		"allow_users_to_reset_machines":                    ssSettings.GetAllowUsersToResetMachines(),
+		"delete_or_refresh_machine_after_logoff":           ssSettings.GetDeleteOrRefreshMachineAfterLogoff(),
+		"disconnected_session_timeout_minutes":             ssSettings.GetDisconnectedSessionTimeoutMinutes(),
+		"disconnected_session_timeout_policy":              ssSettings.GetDisconnectedSessionTimeoutPolicy(),
+		"empty_session_timeout_minutes":                    ssSettings.GetEmptySessionTimeoutMinutes(),
+		"empty_session_timeout_policy":                     ssSettings.GetEmptySessionTimeoutPolicy(),
+		"logoff_after_timeout":                             ssSettings.GetLogoffAfterTimeout(),
+		"power_policy":                                     ssSettings.GetPowerPolicy(),
+		"pre_launch_session_timeout_minutes":               ssSettings.GetPreLaunchSessionTimeoutMinutes(),
+		"pre_launch_session_timeout_policy":                ssSettings.GetPreLaunchSessionTimeoutPolicy(),
+		"refresh_os_disk_after_logoff":                     ssSettings.GetRefreshOsDiskAfterLogoff(),
+		"refresh_period_days_for_replica_os_disk":          ssSettings.GetRefreshPeriodDaysForReplicaOsDisk(),
+		"refresh_threshold_percentage_for_replica_os_disk": ssSettings.GetRefreshThresholdPercentageForReplicaOsDisk(),
+		"session_timeout_policy":                           ssSettings.GetSessionTimeoutPolicy(),
+	}
+
+	// settings that do not apply to the desktop pool are not returned, so keep their defaults to avoid a diff
+	defaults := map[string]interface{}{
+		"delete_or_refresh_machine_after_logoff": "NEVER",
+		"empty_session_timeout_minutes":          1,
+		"empty_session_timeout_policy":           "AFTER",
+		"pre_launch_session_timeout_minutes":     10,
+		"pre_launch_session_timeout_policy":      "AFTER",
+		"refresh_os_disk_after_logoff":           "NEVER",
+		"session_timeout_policy":                 "DEFAULT",
+	}
+	for k, v := range defaults {
+		if session[k] == "" || session[k] == int32(0) {
+			session[k] = v
+		}
+	}
+
+	return []interface{}{session}
+}
+
+func flattenDesktopPoolClonePrepSettings(custSettings *gohorizon.DesktopPoolCustomizationSettings) []interface{} {
+	clonePrep := map[string]interface{}{
+		"ad_container_rdn":                custSettings.GetAdContainerRdn(),
+		"instant_clone_domain_account_id": custSettings.GetInstantCloneDomainAccountId(),
+		"reuse_pre_existing_accounts":     custSettings.GetReusePreExistingAccounts(),
+	}
+
+	if custSettings.CloneprepCustomizationSettings != nil {
+		clonePrepSettings := custSettings.CloneprepCustomizationSettings
+		clonePrep["priming_computer_account"] = clonePrepSettings.GetPrimingComputerAccount()
+		clonePrep["post_synchronization_script_name"] = clonePrepSettings.GetPostSynchronizationScriptName()
+		clonePrep["post_synchronization_script_parameters"] = clonePrepSettings.GetPostSynchronizationScriptParameters()
+		clonePrep["power_off_script_name"] = clonePrepSettings.GetPowerOffScriptName()
+		clonePrep["power_off_script_parameters"] = clonePrepSettings.GetPowerOffScriptParameters()
+	}
+
+	return []interface{}{clonePrep}
+}
+
+func flattenDesktopPoolSysPrepSettings(custSettings *gohorizon.DesktopPoolCustomizationSettings) []interface{} {
+	sysPrep := map[string]interface{}{
+		"sysprep_customization_spec_id": custSettings.GetSysprepCustomizationSpecId(),
+	}
+
+	return []interface{}{sysPrep}
+}
+
+func flattenDesktopPoolDisplayProtocolSettings(dpSettings *gohorizon.DesktopPoolDisplayProtocolSettings) []interface{} {
+	displayProtocol := map[string]interface{}{
+		"allow_users_to_choose_protocol":    dpSettings.GetAllowUsersToChooseProtocol(),
+		"default_display_protocol":          dpSettings.GetDefaultDisplayProtocol(),
+		"grid_vgpus_enabled":                dpSettings.GetGridVgpusEnabled(),
+		"max_number_of_monitors":            dpSettings.GetMaxNumberOfMonitors(),
+		"max_resolution_of_any_one_monitor": dpSettings.GetMaxResolutionOfAnyOneMonitor(),
+		"renderer_3d":                       dpSettings.GetRenderer3d(),
+		"session_collaboration_enabled":     dpSettings.GetSessionCollaborationEnabled(),
+		"vram_size_mb":                      dpSettings.GetVramSizeMb(),
+	}
+
+	return []interface{}{displayProtocol}
+}
+
+func flattenDesktopPoolPatternNamingSettings(pnSettings *gohorizon.DesktopPoolVirtualMachinePatternNamingSettings) []interface{} {
+	patternNaming := map[string]interface{}{
+		"naming_pattern":           pnSettings.GetNamingPattern(),
+		"provisioning_time":        pnSettings.GetProvisioningTime(),
+		"max_number_of_machines":   pnSettings.GetMaxNumberOfMachines(),
+		"min_number_of_machines":   pnSettings.GetMinNumberOfMachines(),
+		"number_of_spare_machines": pnSettings.GetNumberOfSpareMachines(),
+	}
+
+	return []interface{}{patternNaming}
+}
+
+func flattenDesktopPoolSpecificNamingSettings(snSettings *gohorizon.DesktopPoolVirtualMachineSpecifiedNamingSettings, machines []gohorizon.MachineInfo) []interface{} {
+	specifiedNames := []string{}
+	for _, machine := range machines {
+		// machines whose names were removed are deleted asynchronously
+		if machine.GetState() == "DELETING" {
+			continue
+		}
+		specifiedNames = append(specifiedNames, machine.GetName())
+	}
+
+	specificNaming := map[string]interface{}{
+		"specified_names":                         specifiedNames,
+		"num_unassigned_machines_kept_powered_on": snSettings.GetNumUnassignedMachinesKeptPoweredOn(),
+		"start_machines_in_maintenance_mode":      snSettings.GetStartMachinesInMaintenanceMode(),
+	}
+
+	return []interface{}{specificNaming}
+}
+
+func flattenDesktopPoolProvisioningSettings(provSettings *gohorizon.DesktopPoolProvisioningSettings, statusData *gohorizon.DesktopPoolProvisioningStatusData) []interface{} {
+	baseSnapshotID := provSettings.GetBaseSnapshotId()
+	imStreamID := provSettings.GetImStreamId()
+	imTagID := provSettings.GetImTagId()
+	parentVMID := provSettings.GetParentVmId()
+	vmTemplateID := provSettings.GetVmTemplateId()
+
+	// an image that has been pushed but not yet applied is what the pool is configured to use
+	switch {
+	case statusData.GetInstantClonePendingImStreamId() != "":
+		imStreamID = statusData.GetInstantClonePendingImStreamId()
+		imTagID = statusData.GetInstantClonePendingImTagId()
+	case statusData.GetInstantClonePendingImageParentVmId() != "":
+		imStreamID = ""
+		imTagID = ""
+		parentVMID = statusData.GetInstantClonePendingImageParentVmId()
+		baseSnapshotID = statusData.GetInstantClonePendingImageSnapshotId()
+	}
+
+	// pools created from an Image Management Stream are backed by VMs managed by Horizon
+	if imStreamID != "" {
+		baseSnapshotID = ""
+		parentVMID = ""
+		vmTemplateID = ""
+	}
+
+	provisioning := map[string]interface{}{
+		"host_or_cluster_id": provSettings.GetHostOrClusterId(),
+		"resource_pool_id":   provSettings.GetResourcePoolId(),
+		"vm_folder_id":       provSettings.GetVmFolderId(),
+		"add_virtual_tpm":    provSettings.GetAddVirtualTpm(),
+		"base_snapshot_id":   baseSnapshotID,
+		"datacenter_id":      provSettings.GetDatacenterId(),
+		"im_stream_id":       imStreamID,
+		"im_tag_id":          imTagID,
+		"parent_vm_id":       parentVMID,
+		"vm_template_id":     vmTemplateID,
+	}
+
+	return []interface{}{provisioning}
+}
+
+func flattenDesktopPoolStorageSettings(storSettings *gohorizon.DesktopPoolStorageSettings) []interface{} {
+	datastores := []interface{}{}
+	for _, datastore := range storSettings.GetDatastores() {
+		datastores = append(datastores, map[string]interface{}{
+			"datastore_id": datastore.GetDatastoreId(),
+			"sdrs_cluster": datastore.GetSdrsCluster(),
+		})
+	}
+
+	storage := map[string]interface{}{
+		"datastores":                datastores,
+		"reclaim_vm_disk_space":     storSettings.GetReclaimVmDiskSpace(),
+		"replica_disk_datastore_id": storSettings.GetReplicaDiskDatastoreId(),
+		"use_vsan":                  storSettings.GetUseVsan(),
+		"use_separate_datastores_replica_and_os_disks": storSettings.GetUseSeparateDatastoresReplicaAndOsDisks(),
+	}
+
+	// the threshold is only meaningful when reclamation is enabled
+	if storSettings.GetReclaimVmDiskSpace() {
+		storage["reclamation_threshold_mb"] = storSettings.GetReclamationThresholdMb()
+	}
+
+	return []interface{}{storage}
+}
+
+// validateBlackoutTime checks that a View Storage Accelerator blackout time is in 24-hour HH:MM format.
+var validateBlackoutTime = validation.StringMatch(
+	regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`),
+	"must be a time in 24-hour HH:MM format",
+)
+
+func expandDesktopPoolViewStorageAcceleratorSettingsCreateSpec(raw []interface{}) *gohorizon.DesktopPoolViewStorageAcceleratorSettingsCreateSpec {
+	vsaSettingsRaw := raw[0].(map[string]interface{})
+	regenerateDays := int32(vsaSettingsRaw["regenerate_view_storage_accelerator_days"].(int))
+	useVSA := vsaSettingsRaw["use_view_storage_accelerator"].(bool)
+
+	blackoutTimes := []gohorizon.ViewStorageAcceleratorBlackoutTimeSettingsCreateSpec{}
+	for _, btRaw := range vsaSettingsRaw["blackout_times"].([]interface{}) {
+		bt := btRaw.(map[string]interface{})
+		days := []string{}
+		for _, day := range bt["days"].([]interface{}) {
+			days = append(days, day.(string))
+		}
+		blackoutTimes = append(blackoutTimes, *gohorizon.NewViewStorageAcceleratorBlackoutTimeSettingsCreateSpec(days, bt["end_time"].(string), bt["start_time"].(string)))
+	}
+
+	vsaSettings := gohorizon.NewDesktopPoolViewStorageAcceleratorSettingsCreateSpec()
+	vsaSettings.BlackoutTimes = &blackoutTimes
+	vsaSettings.RegenerateViewStorageAcceleratorDays = &regenerateDays
+	vsaSettings.UseViewStorageAccelerator = &useVSA
+
+	return vsaSettings
+}
+
+func expandDesktopPoolViewStorageAcceleratorSettingsUpdateSpec(raw []interface{}) *gohorizon.DesktopPoolViewStorageAcceleratorSettingsUpdateSpec {
+	vsaSettingsRaw := raw[0].(map[string]interface{})
+	regenerateDays := int32(vsaSettingsRaw["regenerate_view_storage_accelerator_days"].(int))
+	useVSA := vsaSettingsRaw["use_view_storage_accelerator"].(bool)
+
+	blackoutTimes := []gohorizon.ViewStorageAcceleratorBlackoutTimeSettingsUpdateSpec{}
+	for _, btRaw := range vsaSettingsRaw["blackout_times"].([]interface{}) {
+		bt := btRaw.(map[string]interface{})
+		days := []string{}
+		for _, day := range bt["days"].([]interface{}) {
+			days = append(days, day.(string))
+		}
+		blackoutTimes = append(blackoutTimes, *gohorizon.NewViewStorageAcceleratorBlackoutTimeSettingsUpdateSpec(days, bt["end_time"].(string), bt["start_time"].(string)))
+	}
+
+	vsaSettings := gohorizon.NewDesktopPoolViewStorageAcceleratorSettingsUpdateSpec()
+	vsaSettings.BlackoutTimes = &blackoutTimes
+	vsaSettings.RegenerateViewStorageAcceleratorDays = &regenerateDays
+	vsaSettings.UseViewStorageAccelerator = &useVSA
+
+	return vsaSettings
+}
+
+func flattenDesktopPoolViewStorageAcceleratorSettings(vsaSettings *gohorizon.DesktopPoolViewStorageAcceleratorSettings) []interface{} {
+	blackoutTimes := []interface{}{}
+	for _, blackoutTime := range vsaSettings.GetBlackoutTimes() {
+		blackoutTimes = append(blackoutTimes, map[string]interface{}{
+			"days":       blackoutTime.GetDays(),
+			"end_time":   blackoutTime.GetEndTime(),
+			"start_time": blackoutTime.GetStartTime(),
+		})
+	}
+
+	vsa := map[string]interface{}{
+		"blackout_times": blackoutTimes,
+		"regenerate_view_storage_accelerator_days": vsaSettings.GetRegenerateViewStorageAcceleratorDays(),
+		"use_view_storage_accelerator":             vsaSettings.GetUseViewStorageAccelerator(),
+		"view_storage_accelerator_disk_types":      vsaSettings.GetViewStorageAcceleratorDiskTypes(),
+	}
+
+	return []interface{}{vsa}
+}
+
+func resourceDesktopPoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+	source := d.Get("source").(string)
+	namingMethod := d.Get("naming_method").(string)
+	enableProvisioning := d.Get("enable_provisioning").(bool)
+
+	// instant clone desktop pools only change images through a push image operation
+	imageChanged := source == "INSTANT_CLONE" && d.HasChanges("provisioning_settings.0.parent_vm_id", "provisioning_settings.0.base_snapshot_id", "provisioning_settings.0.im_stream_id", "provisioning_settings.0.im_tag_id")
+
+	body := expandDesktopPoolUpdateSpec(d)
+
+	resp, err := client.InventoryApi.UpdateDesktopPool(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if namingMethod == "SPECIFIED" && d.HasChange("specific_naming_settings.0.specified_names") {
+		if diags := resourceDesktopPoolUpdateSpecifiedNames(ctx, d, &client); diags != nil {
+			return diags
+		}
+	}
+
+	if imageChanged {
+		if diags := resourceDesktopPoolPushImage(ctx, d, &client); diags != nil {
+			return diags
+		}
+	}
+
+	// only changes that provision or replace machines are waited for
+	provisioningChanged := imageChanged || d.HasChanges("enable_provisioning", "pattern_naming_settings", "specific_naming_settings", "provisioning_settings", "wait_for_provisioning")
+	if _, ok := d.GetOk("wait_for_provisioning"); ok && enableProvisioning && provisioningChanged {
+		if diags := resourceDesktopPoolWaitForProvisioning(ctx, d, &client, d.Timeout(schema.TimeoutUpdate)); diags != nil {
+			return diags
+		}
+	}
+
+	return resourceDesktopPoolRead(ctx, d, meta)
+}
+
+// expandDesktopPoolUpdateSpec builds the update spec of an automated desktop pool. The update replaces
+// the whole desktop pool configuration, so it is built from all of the settings.
+func expandDesktopPoolUpdateSpec(d *schema.ResourceData) *gohorizon.DesktopPoolUpdateSpec {
+	source := d.Get("source").(string)
+	userAssignment := d.Get("user_assignment").(string)
+	namingMethod := d.Get("naming_method").(string)
+	custType := d.Get("customization_type").(string)
+
+	cloudAssigned := d.Get("cloud_assigned").(bool)
+	cloudManaged := d.Get("cloud_managed").(bool)
+	displayAssigned := d.Get("display_assigned_machine_name").(bool)
+	displayAlias := d.Get("display_machine_alias").(bool)
+	displayName := d.Get("display_name").(string)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	enabled := d.Get("enabled").(bool)
+	enableProvisioning := d.Get("enable_provisioning").(bool)
+	stopOnErr := d.Get("stop_provisioning_on_error").(bool)
+	agID := d.Get("access_group_id").(string)
+	sessionType := d.Get("session_type").(string)
+	tpsScope := d.Get("transparent_page_sharing_scope").(string)
+
+	body := gohorizon.NewDesktopPoolUpdateSpec(cloudAssigned, cloudManaged, displayAssigned, displayAlias, displayName, clientRestrictions, enabled)
+	body.AccessGroupId = &agID
+	body.EnableProvisioning = &enableProvisioning
+	body.StopProvisioningOnError = &stopOnErr
+	body.SessionType = &sessionType
+	body.TransparentPageSharingScope = &tpsScope
+
+	if userAssignment == "DEDICATED" {
+		autoAssign := d.Get("automatic_user_assignment").(bool)
+		multiAssign := d.Get("allow_multiple_user_assignments").(bool)
+		body.AutomaticUserAssignment = &autoAssign
+		body.AllowMultipleUserAssignments = &multiAssign
+	}
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	description := d.Get("description").(string)
+	body.Description = &description
+
+	csTags := []string{}
+	for _, tag := range d.Get("cs_restriction_tags").(*schema.Set).List() {
+		csTags = append(csTags, tag.(string))
+	}
+	body.CsRestrictionTags = &csTags
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := []string{}
+		for _, location := range sl.(*schema.Set).List() {
+			shortcutLocations = append(shortcutLocations, location.(string))
+		}
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	if namingMethod == "PATTERN" {
+		patternNamingRaw := d.Get("pattern_naming_settings").([]interface{})[0].(map[string]interface{})
+		namingPattern := patternNamingRaw["naming_pattern"].(string)
+		provTime := patternNamingRaw["provisioning_time"].(string)
+		maxMachine := int32(patternNamingRaw["max_number_of_machines"].(int))
+		numSpare := int32(patternNamingRaw["number_of_spare_machines"].(int))
+		patternNaming := gohorizon.NewDesktopPoolVirtualMachinePatternNamingSettingsUpdateSpec(maxMachine, namingPattern, numSpare, provTime)
+
+		if patternNamingRaw["min_number_of_machines"].(int) > 0 {
+			minMachine := int32(patternNamingRaw["min_number_of_machines"].(int))
+			patternNaming.MinNumberOfMachines = &minMachine
+		}
+
+		body.PatternNamingSettings = patternNaming
+	}
+
+	if namingMethod == "SPECIFIED" {
+		specificNamingRaw := d.Get("specific_naming_settings").([]interface{})[0].(map[string]interface{})
+		numPoweredOn := int32(specificNamingRaw["num_unassigned_machines_kept_powered_on"].(int))
+		maintenanceMode := specificNamingRaw["start_machines_in_maintenance_mode"].(bool)
+
+		// the machines themselves are reconciled separately after the update
+		body.SpecificNamingSettings = gohorizon.NewDesktopPoolVirtualMachineSpecifiedNamingSettingsUpdateSpec(numPoweredOn, maintenanceMode)
+	}
+
+	provSettingsRaw := d.Get("provisioning_settings").([]interface{})[0].(map[string]interface{})
+	hcID := provSettingsRaw["host_or_cluster_id"].(string)
+	rpID := provSettingsRaw["resource_pool_id"].(string)
+	provSettings := gohorizon.NewDesktopPoolProvisioningSettingsUpdateSpec(hcID, rpID)
+
+	if source == "VIRTUAL_CENTER" {
+		if imStreamID := provSettingsRaw["im_stream_id"].(string); imStreamID != "" {
+			imTagID := provSettingsRaw["im_tag_id"].(string)
+			provSettings.ImStreamId = &imStreamID
+			provSettings.ImTagId = &imTagID
+		} else {
+			templateID := provSettingsRaw["vm_template_id"].(string)
+			provSettings.VmTemplateId = &templateID
+		}
+	}
+
+	body.ProvisioningSettings = provSettings
+
+	if d.HasChange("nics") {
+		nics := expandDesktopPoolNicsUpdateSpec(d.Get("nics").([]interface{}))
+		body.Nics = &nics
+	}
+
+	custSettings := gohorizon.NewDesktopPoolCustomizationSettingsUpdateSpec(custType)
+	if source == "VIRTUAL_CENTER" {
+		doNotPowerOn := d.Get("do_not_power_on_vms_after_creation").(bool)
+		custSettings.DoNotPowerOnVmsAfterCreation = &doNotPowerOn
+	}
+	switch custType {
+	case "SYS_PREP":
+		sysPrep := d.Get("sys_prep_settings").([]interface{})[0].(map[string]interface{})
+		spCustID := sysPrep["sysprep_customization_spec_id"].(string)
+		custSettings.SysprepCustomizationSpecId = &spCustID
+	case "CLONE_PREP":
+		clonePrep := d.Get("clone_prep_settings").([]interface{})[0].(map[string]interface{})
+		clonePrepSettings := gohorizon.NewDesktopPoolCloneprepCustomizationSettingsUpdateSpec()
+
+		adRDN := clonePrep["ad_container_rdn"].(string)
+		icdaID := clonePrep["instant_clone_domain_account_id"].(string)
+		pca := clonePrep["priming_computer_account"].(string)
+		psScriptName := clonePrep["post_synchronization_script_name"].(string)
+		psScriptParams := clonePrep["post_synchronization_script_parameters"].(string)
+		poScriptName := clonePrep["power_off_script_name"].(string)
+		poScriptParams := clonePrep["power_off_script_parameters"].(string)
+		reuse := clonePrep["reuse_pre_existing_accounts"].(bool)
+
+		clonePrepSettings.PostSynchronizationScriptName = &psScriptName
+		clonePrepSettings.PostSynchronizationScriptParameters = &psScriptParams
+		clonePrepSettings.PowerOffScriptName = &poScriptName
+		clonePrepSettings.PowerOffScriptParameters = &poScriptParams
+		clonePrepSettings.PrimingComputerAccount = &pca
+
+		custSettings.AdContainerRdn = &adRDN
+		custSettings.InstantCloneDomainAccountId = &icdaID
+		custSettings.ReusePreExistingAccounts = &reuse
+		custSettings.CloneprepCustomizationSettings = clonePrepSettings
+	}
+
+	body.CustomizationSettings = custSettings
+
+	storSetRaw := d.Get("storage_settings").([]interface{})[0].(map[string]interface{})
+	datastoresRaw := storSetRaw["datastores"].(*schema.Set).List()
+	datastores := []gohorizon.DesktopPoolDatastoreSettingsUpdateSpec{}
+	for _, raw := range datastoresRaw {
+		rawds := raw.(map[string]interface{})
+		datastore := gohorizon.NewDesktopPoolDatastoreSettingsUpdateSpec(rawds["datastore_id"].(string))
+		sdrs := rawds["sdrs_cluster"].(bool)
+		if source == "VIRTUAL_CENTER" {
+			datastore.SdrsCluster = &sdrs
+		}
+		datastores = append(datastores, *datastore)
+	}
+	reclaim := storSetRaw["reclaim_vm_disk_space"].(bool)
+	reclaimThresh := int64(storSetRaw["reclamation_threshold_mb"].(int))
+	rddID := storSetRaw["replica_disk_datastore_id"].(string)
+	separateds := storSetRaw["use_separate_datastores_replica_and_os_disks"].(bool)
+	vSAN := storSetRaw["use_vsan"].(bool)
+
+	storageSettings := gohorizon.NewDesktopPoolStorageSettingsUpdateSpec(datastores, vSAN)
+
+	if rddID != "" {
+		storageSettings.ReplicaDiskDatastoreId = &rddID
+		storageSettings.UseSeparateDatastoresReplicaAndOsDisks = &separateds
+	}
+
+	if reclaim {
+		storageSettings.ReclaimVmDiskSpace = &reclaim
+		storageSettings.ReclamationThresholdMb = &reclaimThresh
+	}
+
+	body.StorageSettings = storageSettings
+
+	if dps, ok := d.GetOk("display_protocol_settings"); ok {
+		body.DisplayProtocolSettings = expandDesktopPoolDisplayProtocolSettingsUpdateSpec(dps.([]interface{}))
+	}
+
+	if vsas, ok := d.GetOk("view_storage_accelerator_settings"); ok {
+		body.ViewStorageAcceleratorSettings = expandDesktopPoolViewStorageAcceleratorSettingsUpdateSpec(vsas.([]interface{}))
+	}
+
+	if ss, ok := d.GetOk("session_settings"); ok {
+		body.SessionSettings = expandDesktopPoolSessionSettingsUpdateSpec(ss.([]interface{}), sessionType, userAssignment)
+	}
+
+	return body
+}
+
+// desktopPoolTransitionalMachineStates are the machine states that indicate provisioning is still in progress.
+var desktopPoolTransitionalMachineStates = map[string]bool{
+	"PROVISIONING":      true,
+	"CUSTOMIZING":       true,
+	"WAITING_FOR_AGENT": true,
+	"IN_PROGRESS":       true,
+	"VALIDATING":        true,
+}
+
+// desktopPoolExpectedMachineCount returns the number of machines the naming settings of the desktop pool provision.
+func desktopPoolExpectedMachineCount(d *schema.ResourceData) int {
+	if d.Get("naming_method").(string) == "SPECIFIED" {
+		return d.Get("specific_naming_settings.0.specified_names").(*schema.Set).Len()
+	}
+
+	maxMachines := d.Get("pattern_naming_settings.0.max_number_of_machines").(int)
+	if d.Get("pattern_naming_settings.0.provisioning_time").(string) == "UP_FRONT" {
+		return maxMachines
+	}
+
+	expected := d.Get("pattern_naming_settings.0.min_number_of_machines").(int)
+	if spare := d.Get("pattern_naming_settings.0.number_of_spare_machines").(int); spare > expected {
+		expected = spare
+	}
+	if expected > maxMachines {
+		expected = maxMachines
+	}
+
+	return expected
+}
+
+// resourceDesktopPoolWaitForProvisioning polls the machines of the desktop pool until the wait_for_provisioning
+// condition is met, provisioning is disabled by an error or the timeout expires.
+func resourceDesktopPoolWaitForProvisioning(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient, timeout time.Duration) diag.Diagnostics {
+	id := d.Id()
+	condition := d.Get("wait_for_provisioning.0.condition").(string)
+	machineCount := d.Get("wait_for_provisioning.0.machine_count").(int)
+	expectedCount := desktopPoolExpectedMachineCount(d)
+
+	// diagnostics of a failed refresh are kept here because the refresh function can only return an error
+	var refreshDiags diag.Diagnostics
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"PENDING"},
+		Target:  []string{"DONE"},
+		Refresh: func() (interface{}, string, error) {
+			poolInfo, _, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+			if err != nil {
+				return nil, "", err
+			}
+
+			status := poolInfo.GetProvisioningStatusData()
+			if !poolInfo.GetEnableProvisioning() && status.GetLastProvisioningError() != "" {
+				return poolInfo, "FAILED", nil
+			}
+
+			machines, diags := listDesktopPoolMachines(ctx, client, id)
+			if diags != nil {
+				refreshDiags = diags
+				return nil, "", fmt.Errorf("error listing machines of desktop pool %s", id)
+			}
+
+			available := 0
+			provisioned := 0
+			inProgress := false
+			for _, machine := range machines {
+				switch {
+				case machine.GetState() == "DELETING":
+					continue
+				case machine.GetState() == "AVAILABLE":
+					available++
+				case desktopPoolTransitionalMachineStates[machine.GetState()]:
+					inProgress = true
+				}
+				provisioned++
+			}
+
+			switch condition {
+			case "MACHINES_AVAILABLE":
+				if available >= machineCount {
+					return poolInfo, "DONE", nil
+				}
+			case "PROVISIONING_COMPLETE":
+				if provisioned >= expectedCount && !inProgress && status.GetInstantCloneOperation() != "INITIAL_PUBLISH" {
+					return poolInfo, "DONE", nil
+				}
+			}
+
+			return poolInfo, "PENDING", nil
+		},
+		Timeout:    timeout,
+		Delay:      desktopPoolPollInterval,
+		MinTimeout: desktopPoolPollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err == nil {
+		return nil
+	}
+	if refreshDiags != nil {
+		return refreshDiags
+	}
+
+	diags := returnResponseErr(nil, err)
+
+	poolInfo, _, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+	if err != nil {
+		return diags
+	}
+
+	status := poolInfo.GetProvisioningStatusData()
+	if !poolInfo.GetEnableProvisioning() && status.GetLastProvisioningError() != "" {
+		diags = diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "provisioning was disabled because of an error",
+			Detail:   status.GetLastProvisioningError(),
+		}}
+	}
+
+	machines, machineDiags := listDesktopPoolMachines(ctx, client, id)
+	if machineDiags != nil {
+		return append(diags, machineDiags...)
+	}
+
+	return append(diags, desktopPoolMachineErrors(machines)...)
+}
+
+// desktopPoolPollInterval is the interval at which a desktop pool is polled while waiting for provisioning or for a
+// push image operation.
+var desktopPoolPollInterval = 10 * time.Second
+
+// resourceDesktopPoolPushImage schedules a push of the configured image to an instant clone desktop pool
+// and, if requested, waits for the operation to finish.
+func resourceDesktopPoolPushImage(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient) diag.Diagnostics {
+	id := d.Id()
+	provSettingsRaw := d.Get("provisioning_settings").([]interface{})[0].(map[string]interface{})
+	imStreamID := provSettingsRaw["im_stream_id"].(string)
+	imTagID := provSettingsRaw["im_tag_id"].(string)
+	parentVMID := provSettingsRaw["parent_vm_id"].(string)
+	baseSnapshotID := provSettingsRaw["base_snapshot_id"].(string)
+
+	logoffPolicy := "WAIT_FOR_LOGOFF"
+	startTime := ""
+	stopOnFirstError := true
+	waitForCompletion := false
+	if pis, ok := d.GetOk("push_image_settings"); ok {
+		pushImageRaw := pis.([]interface{})[0].(map[string]interface{})
+		logoffPolicy = pushImageRaw["logoff_policy"].(string)
+		startTime = pushImageRaw["start_time"].(string)
+		stopOnFirstError = pushImageRaw["stop_on_first_error"].(bool)
+		waitForCompletion = pushImageRaw["wait_for_completion"].(bool)
+	}
+
+	pushSpec := gohorizon.NewDesktopPoolPushImageSpec(logoffPolicy)
+	pushSpec.StopOnFirstError = &stopOnFirstError
+	if imStreamID != "" {
+		pushSpec.ImStreamId = &imStreamID
+		pushSpec.ImTagId = &imTagID
+	} else {
+		pushSpec.ParentVmId = &parentVMID
+		pushSpec.SnapshotId = &baseSnapshotID
+	}
+
+	if startTime != "" {
+		st, err := time.Parse(time.RFC3339, startTime)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		startTimeMs := st.UnixMilli()
+		pushSpec.StartTime = &startTimeMs
+	}
+
+	resp, err := client.InventoryApi.SchedulePushImage(ctx, id).Body(*pushSpec).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if !waitForCompletion {
+		return nil
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"PENDING"},
+		Target:  []string{"DONE"},
+		Refresh: func() (interface{}, string, error) {
+			poolInfo, _, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+			if err != nil {
+				return nil, "", err
+			}
+
+			status := poolInfo.GetProvisioningStatusData()
+			switch status.GetInstantClonePendingImageState() {
+			case "FAILED":
+				return poolInfo, "FAILED", nil
+			case "":
+				if status.GetInstantCloneOperation() == "NONE" || status.GetInstantCloneOperation() == "" {
+					return poolInfo, "DONE", nil
+				}
+			}
+
+			return poolInfo, "PENDING", nil
+		},
+		Timeout:    d.Timeout(schema.TimeoutUpdate),
+		Delay:      desktopPoolPollInterval,
+		MinTimeout: desktopPoolPollInterval,
+	}
+
+	_, err = stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return desktopPoolPushImageErrors(ctx, client, id, err)
+	}
+
+	machines, diags := listDesktopPoolMachines(ctx, client, id)
+	if diags != nil {
+		return diags
+	}
+
+	return desktopPoolMachineErrors(machines)
+}
+
+// desktopPoolPushImageErrors converts a failed wait for a push image operation into diagnostics
+// containing the pending image error and the errors of the machines in the desktop pool.
+func desktopPoolPushImageErrors(ctx context.Context, client *gohorizon.APIClient, id string, waitErr error) diag.Diagnostics {
+	poolInfo, _, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(nil, waitErr)
+	}
+
+	status := poolInfo.GetProvisioningStatusData()
+	if status.GetInstantClonePendingImageState() != "FAILED" {
+		return returnResponseErr(nil, waitErr)
+	}
+
+	diags := diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "push image operation failed",
+		Detail:   status.GetInstantClonePendingImageError(),
+	}}
+
+	machines, machineDiags := listDesktopPoolMachines(ctx, client, id)
+	if machineDiags != nil {
+		return append(diags, machineDiags...)
+	}
+
+	return append(diags, desktopPoolMachineErrors(machines)...)
+}
+
+// resourceDesktopPoolUpdateSpecifiedNames provisions machines for added specified names and deletes the machines of removed names.
+func resourceDesktopPoolUpdateSpecifiedNames(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient) diag.Diagnostics {
+	id := d.Id()
+	specifiedNames := expandStringSet(d.Get("specific_naming_settings.0.specified_names").(*schema.Set))
+
+	machines, diags := listDesktopPoolMachines(ctx, client, id)
+	if diags != nil {
+		return diags
+	}
+
+	machineIDs := map[string]string{}
+	currentNames := []string{}
+	for _, machine := range machines {
+		if machine.GetState() == "DELETING" {
+			continue
+		}
+		machineIDs[machine.GetName()] = machine.GetId()
+		currentNames = append(currentNames, machine.GetName())
+	}
+
+	addList, removeList := diffStringSets(currentNames, specifiedNames)
+
+	if len(removeList) > 0 {
+		removeIDs := []string{}
+		for _, name := range removeList {
+			removeIDs = append(removeIDs, machineIDs[name])
+		}
+
+		deleteFromDisk := true
+		deleteSpec := gohorizon.NewMachineDeleteSpec(removeIDs)
+		deleteSpec.MachineDeleteData = gohorizon.NewMachineDeleteData()
+		deleteSpec.MachineDeleteData.DeleteFromDisk = &deleteFromDisk
+
+		results, resp, err := client.InventoryApi.DeleteMachines(ctx).Body(*deleteSpec).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	if len(addList) > 0 {
+		addNames := []gohorizon.MachineSpecifiedName{}
+		for _, name := range addList {
+			addNames = append(addNames, *gohorizon.NewMachineSpecifiedName(name))
+		}
+
+		results, resp, err := client.InventoryApi.AddMachinesByName(ctx, id).Body(addNames).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	return nil
+}
+
+// desktopPoolDeleteFromDisk returns whether the machine VMs of an automated desktop pool are deleted from
+// vCenter Server when it is deleted, or nil to leave it to the API default.
+func desktopPoolDeleteFromDisk(d *schema.ResourceData) *bool {
+	deleteFromDisk := true
+	if _, ok := d.GetOk("delete_settings"); ok {
+		deleteFromDisk = d.Get("delete_settings.0.delete_from_disk").(bool)
+		return &deleteFromDisk
+	}
+
+	// instant clones can only be deleted together with their VMs
+	if d.Get("source").(string) == "INSTANT_CLONE" {
+		return &deleteFromDisk
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolAutomatedDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	deleteFromDisk := desktopPoolDeleteFromDisk(d)
+	archive := false
+	forceLogoff := false
+	if _, ok := d.GetOk("delete_settings"); ok {
+		archive = d.Get("delete_settings.0.archive_persistent_disks").(bool)
+		forceLogoff = d.Get("delete_settings.0.force_logoff_sessions").(bool)
+	}
+
+	// the desktop pool delete spec can not log off sessions or archive disks so the machines are deleted first,
+	// after disabling provisioning so that Horizon does not provision replacements for them
+	if archive || forceLogoff {
+		body := expandDesktopPoolUpdateSpec(d)
+		disableProvisioning := false
+		body.EnableProvisioning = &disableProvisioning
+
+		resp, err := client.InventoryApi.UpdateDesktopPool(ctx, id).Body(*body).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+
+		machines, diags := listDesktopPoolMachines(ctx, &client, id)
+		if diags != nil {
+			return diags
+		}
+
+		machineIDs := []string{}
+		for _, machine := range machines {
+			if machine.GetState() != "DELETING" {
+				machineIDs = append(machineIDs, machine.GetId())
+			}
+		}
+
+		if len(machineIDs) > 0 {
+			deleteSpec := gohorizon.NewMachineDeleteSpec(machineIDs)
+			deleteSpec.MachineDeleteData = gohorizon.NewMachineDeleteData()
+			deleteSpec.MachineDeleteData.DeleteFromDisk = deleteFromDisk
+			deleteSpec.MachineDeleteData.ForceLogoffSession = &forceLogoff
+			if archive {
+				deleteSpec.MachineDeleteData.ArchivePersistentDisk = &archive
+			}
+
+			results, resp, err := client.InventoryApi.DeleteMachines(ctx).Body(*deleteSpec).Execute()
+			if err != nil {
+				return returnResponseErr(resp, err)
+			}
+			if diags := bulkItemResponseErrors(results); diags != nil {
+				return diags
+			}
+		}
+	}
+
+	var deleteSpec *gohorizon.DesktopPoolDeleteSpec
+	if deleteFromDisk != nil {
+		deleteSpec = gohorizon.NewDesktopPoolDeleteSpec()
+		deleteSpec.DeleteFromDisk = deleteFromDisk
+	}
+
+	return deleteDesktopPool(ctx, d, &client, deleteSpec)
+}
+
+// resourceDesktopPoolDelete deletes manual and RDS desktop pools. Their machines are not managed by
+// Horizon, so no delete spec is sent and the VMs are never deleted from vCenter Server.
+func resourceDesktopPoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	return deleteDesktopPool(ctx, d, &client, nil)
+}
+
+// desktopPoolDeletePollInterval is the interval at which a deleted desktop pool is polled until it is gone.
+var desktopPoolDeletePollInterval = 5 * time.Second
+
+// deleteDesktopPool deletes a desktop pool, with deleteSpec if it is not nil, and waits until it is gone.
+func deleteDesktopPool(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient, deleteSpec *gohorizon.DesktopPoolDeleteSpec) diag.Diagnostics {
+	id := d.Id()
+
+	deleteReq := client.InventoryApi.DeleteDesktopPool(ctx, id)
+	if deleteSpec != nil {
+		deleteReq = deleteReq.Body(*deleteSpec)
+	}
+
+	resp, err := deleteReq.Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"DELETING"},
+		Target:  []string{"DELETED"},
+		Refresh: func() (interface{}, string, error) {
+			poolInfo, resp, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return id, "DELETED", nil
+			}
+			if err != nil {
+				return nil, "", err
+			}
+
+			return poolInfo, "DELETING", nil
+		},
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Delay:      desktopPoolDeletePollInterval,
+		MinTimeout: desktopPoolDeletePollInterval,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return append(diag.Errorf("error waiting for desktop pool %s to be deleted", id), returnResponseErr(nil, err)...)
 	}
 
 	d.SetId("")
@@ -1,32 +1,1450 @@
 package provider
 
 import (
-	"regexp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/umich-vci/gohorizon"
 )
 
-func TestAccResourceFarm(t *testing.T) {
-	t.Skip("resource not yet implemented, remove this once you add your own code")
+// testDesktopPoolAutomatedSettings are the settings of a valid instant clone desktop pool, keyed by
+// attribute or block name so that tests can replace or remove them.
+var testDesktopPoolAutomatedSettings = map[string]string{
+	"name":               `name = "ic-pool"`,
+	"access_group_id":    `access_group_id = "access-group"`,
+	"vcenter_id":         `vcenter_id = "vcenter"`,
+	"source":             `source = "INSTANT_CLONE"`,
+	"naming_method":      `naming_method = "PATTERN"`,
+	"user_assignment":    `user_assignment = "FLOATING"`,
+	"customization_type": `customization_type = "CLONE_PREP"`,
+	"clone_prep_settings": `clone_prep_settings {
+    ad_container_rdn                = "OU=VDI"
+    instant_clone_domain_account_id = "account"
+  }`,
+	"pattern_naming_settings": `pattern_naming_settings {
+    naming_pattern         = "ic-{n}"
+    max_number_of_machines = 5
+  }`,
+	"provisioning_settings": `provisioning_settings {
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+    parent_vm_id       = "parent-vm"
+    base_snapshot_id   = "snapshot"
+  }`,
+	"storage_settings": `storage_settings {
+    datastores {
+      datastore_id = "datastore"
+    }
+  }`,
+}
+
+// testDesktopPoolAutomatedConfig returns a desktop pool with settings replaced by overrides.
+// An empty override removes the setting.
+func testDesktopPoolAutomatedConfig(srv *httptest.Server, overrides map[string]string) string {
+	settings := map[string]string{}
+	for k, v := range testDesktopPoolAutomatedSettings {
+		settings[k] = v
+	}
+	for k, v := range overrides {
+		if v == "" {
+			delete(settings, k)
+			continue
+		}
+		settings[k] = v
+	}
+
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	body := make([]string, 0, len(keys))
+	for _, k := range keys {
+		body = append(body, "  "+settings[k])
+	}
+
+	return testProviderConfig(srv) + fmt.Sprintf(`
+resource "horizon_desktop_pool_automated" "test" {
+%s
+}
+`, strings.Join(body, "\n"))
+}
+
+func TestResourceDesktopPoolAutomatedPlan(t *testing.T) {
+	fullClone := map[string]string{
+		"source":              `source = "VIRTUAL_CENTER"`,
+		"customization_type":  `customization_type = "SYS_PREP"`,
+		"clone_prep_settings": "",
+		"sys_prep_settings": `sys_prep_settings {
+    sysprep_customization_spec_id = "spec"
+  }`,
+		"provisioning_settings": `provisioning_settings {
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+    vm_template_id     = "template"
+  }`,
+	}
+	with := func(base map[string]string, overrides map[string]string) map[string]string {
+		merged := map[string]string{}
+		for k, v := range base {
+			merged[k] = v
+		}
+		for k, v := range overrides {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	cases := []struct {
+		name        string
+		overrides   map[string]string
+		extraConfig string
+		expectError string
+	}{
+		{
+			name: "valid instant clone pool",
+		},
+		{
+			name:      "valid full clone pool",
+			overrides: fullClone,
+		},
+		{
+			name: "valid pool with unknown source values",
+			overrides: map[string]string{
+				"provisioning_settings": `provisioning_settings {
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+    parent_vm_id       = horizon_desktop_pool_manual.other.id
+    base_snapshot_id   = horizon_desktop_pool_manual.other.id
+  }`,
+			},
+			extraConfig: `
+resource "horizon_desktop_pool_manual" "other" {
+  name            = "other"
+  access_group_id = "access-group"
+  source          = "UNMANAGED"
+  user_assignment = "FLOATING"
+  machine_ids     = ["machine"]
+}
+`,
+		},
+		{
+			name:        "invalid name",
+			overrides:   map[string]string{"name": `name = "ic pool"`},
+			expectError: `name must contain only alphanumerics`,
+		},
+		{
+			name:        "automatic assignment of floating pool",
+			overrides:   map[string]string{"automatic_user_assignment": `automatic_user_assignment = true`},
+			expectError: `automatic_user_assignment should not be set`,
+		},
+		{
+			name: "automatic and multiple assignment",
+			overrides: map[string]string{
+				"user_assignment":                 `user_assignment = "DEDICATED"`,
+				"automatic_user_assignment":       `automatic_user_assignment = true`,
+				"allow_multiple_user_assignments": `allow_multiple_user_assignments = true`,
+			},
+			expectError: `cannot both be true`,
+		},
+		{
+			name:        "specified naming without settings",
+			overrides:   map[string]string{"naming_method": `naming_method = "SPECIFIED"`},
+			expectError: `specific_naming_settings must be set`,
+		},
+		{
+			name: "pattern naming with specified names",
+			overrides: map[string]string{"specific_naming_settings": `specific_naming_settings {
+    specified_names = ["vdi-1"]
+  }`},
+			expectError: `specific_naming_settings must not be set`,
+		},
+		{
+			name: "more spare machines than machines",
+			overrides: map[string]string{"pattern_naming_settings": `pattern_naming_settings {
+    naming_pattern           = "ic-{n}"
+    max_number_of_machines   = 5
+    number_of_spare_machines = 6
+  }`},
+			expectError: `number_of_spare_machines can not be greater`,
+		},
+		{
+			name: "more minimum machines than machines",
+			overrides: map[string]string{"pattern_naming_settings": `pattern_naming_settings {
+    naming_pattern         = "ic-{n}"
+    provisioning_time      = "ON_DEMAND"
+    max_number_of_machines = 5
+    min_number_of_machines = 6
+  }`},
+			expectError: `min_number_of_machines can not be greater`,
+		},
+		{
+			name: "minimum machines provisioned up front",
+			overrides: map[string]string{"pattern_naming_settings": `pattern_naming_settings {
+    naming_pattern         = "ic-{n}"
+    provisioning_time      = "UP_FRONT"
+    max_number_of_machines = 5
+    min_number_of_machines = 2
+  }`},
+			expectError: `min_number_of_machines can not be set`,
+		},
+		{
+			name: "more powered on machines than names",
+			overrides: map[string]string{
+				"naming_method":           `naming_method = "SPECIFIED"`,
+				"pattern_naming_settings": "",
+				"specific_naming_settings": `specific_naming_settings {
+    specified_names                         = ["vdi-1"]
+    num_unassigned_machines_kept_powered_on = 2
+  }`,
+			},
+			expectError: `num_unassigned_machines_kept_powered_on can not be`,
+		},
+		{
+			name: "image stream without tag",
+			overrides: map[string]string{"provisioning_settings": `provisioning_settings {
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+    im_stream_id       = "stream"
+  }`},
+			expectError: `im_tag_id must be set`,
+		},
+		{
+			name: "instant clone without snapshot",
+			overrides: map[string]string{"provisioning_settings": `provisioning_settings {
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+    parent_vm_id       = "parent-vm"
+  }`},
+			expectError: `parent_vm_id and base_snapshot_id must be set`,
+		},
+		{
+			name: "instant clone with template",
+			overrides: map[string]string{"provisioning_settings": `provisioning_settings {
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+    parent_vm_id       = "parent-vm"
+    base_snapshot_id   = "snapshot"
+    vm_template_id     = "template"
+  }`},
+			expectError: `vm_template_id must not be set`,
+		},
+		{
+			name: "instant clone kept on disk",
+			overrides: map[string]string{"delete_settings": `delete_settings {
+    delete_from_disk = false
+  }`},
+			expectError: `delete_from_disk must be true`,
+		},
+		{
+			name: "full clone without template",
+			overrides: with(fullClone, map[string]string{"provisioning_settings": `provisioning_settings {
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+  }`}),
+			expectError: `vm_template_id must be set`,
+		},
+		{
+			name: "full clone with push image settings",
+			overrides: with(fullClone, map[string]string{"push_image_settings": `push_image_settings {
+    logoff_policy = "FORCE_LOGOFF"
+  }`}),
+			expectError: `push_image_settings can only be set`,
+		},
+		{
+			name: "wait for disabled provisioning",
+			overrides: map[string]string{
+				"enable_provisioning": `enable_provisioning = false`,
+				"wait_for_provisioning": `wait_for_provisioning {
+    condition = "MACHINES_AVAILABLE"
+  }`,
+			},
+		},
+		{
+			name:        "clone prep without settings",
+			overrides:   map[string]string{"clone_prep_settings": ""},
+			expectError: `clone_prep_settings must be specified`,
+		},
+		{
+			name:        "clone prep without powering on",
+			overrides:   map[string]string{"do_not_power_on_vms_after_creation": `do_not_power_on_vms_after_creation = true`},
+			expectError: `do_not_power_on_vms_after_creation can not be set`,
+		},
+		{
+			name:        "no customization with sysprep settings",
+			overrides:   with(fullClone, map[string]string{"customization_type": `customization_type = "NONE"`}),
+			expectError: `sys_prep_settings must not be specified`,
+		},
+		{
+			name: "replica datastore without separate datastores",
+			overrides: map[string]string{"storage_settings": `storage_settings {
+    datastores {
+      datastore_id = "datastore"
+    }
+    replica_disk_datastore_id = "replica"
+  }`},
+			expectError: `replica_disk_datastore_id can only be set`,
+		},
+		{
+			name: "disk space reclamation of instant clones",
+			overrides: map[string]string{"storage_settings": `storage_settings {
+    datastores {
+      datastore_id = "datastore"
+    }
+    reclaim_vm_disk_space = true
+  }`},
+			expectError: `reclaim_vm_disk_space cannot be configured`,
+		},
+		{
+			name: "reclamation threshold without reclamation",
+			overrides: with(fullClone, map[string]string{"storage_settings": `storage_settings {
+    datastores {
+      datastore_id = "datastore"
+    }
+    reclamation_threshold_mb = 1024
+  }`}),
+			expectError: `reclamation_threshold_mb should not be set`,
+		},
+		{
+			name: "storage DRS cluster of instant clones",
+			overrides: map[string]string{"storage_settings": `storage_settings {
+    datastores {
+      datastore_id = "datastore"
+      sdrs_cluster = true
+    }
+  }`},
+			expectError: `sdrs_cluster cannot be configured`,
+		},
+		{
+			name: "3D rendering with protocol choice",
+			overrides: map[string]string{"display_protocol_settings": `display_protocol_settings {
+    renderer_3d                    = "AUTOMATIC"
+    allow_users_to_choose_protocol = true
+  }`},
+			expectError: `allow_users_to_choose_protocol must be false`,
+		},
+		{
+			name: "vGPUs without vSphere 3D rendering",
+			overrides: map[string]string{"display_protocol_settings": `display_protocol_settings {
+    grid_vgpus_enabled = true
+  }`},
+			expectError: `grid_vgpus_enabled can only be true`,
+		},
+		{
+			name: "valid limited network labels",
+			overrides: map[string]string{"nics": `nics {
+    network_interface_card_id = "nic"
+    network_label_assignment_specs {
+      network_label_name = "VDI-1"
+      max_label_type     = "LIMITED"
+      max_label          = 50
+    }
+    network_label_assignment_specs {
+      network_label_name = "VDI-2"
+    }
+  }`},
+		},
+		{
+			name: "limited network label without max_label",
+			overrides: map[string]string{"nics": `nics {
+    network_interface_card_id = "nic"
+    network_label_assignment_specs {
+      network_label_name = "VDI"
+      max_label_type     = "LIMITED"
+    }
+  }`},
+			expectError: `max_label must be set for network label VDI`,
+		},
+		{
+			name: "unlimited network label with max_label",
+			overrides: map[string]string{"nics": `nics {
+    network_interface_card_id = "nic"
+    network_label_assignment_specs {
+      network_label_name = "VDI"
+      max_label          = 50
+    }
+  }`},
+			expectError: `max_label can not be set for network label VDI`,
+		},
+		{
+			name: "valid floating session settings",
+			overrides: map[string]string{"session_settings": `session_settings {
+    allow_multiple_sessions_per_user       = true
+    delete_or_refresh_machine_after_logoff = "REFRESH"
+    disconnected_session_timeout_policy    = "AFTER"
+    disconnected_session_timeout_minutes   = 30
+  }`},
+		},
+		{
+			name: "disconnected timeout without minutes",
+			overrides: map[string]string{"session_settings": `session_settings {
+    disconnected_session_timeout_policy = "AFTER"
+  }`},
+			expectError: `disconnected_session_timeout_minutes must be set`,
+		},
+		{
+			name: "valid dedicated session settings",
+			overrides: map[string]string{
+				"user_assignment": `user_assignment = "DEDICATED"`,
+				"session_settings": `session_settings {
+    refresh_os_disk_after_logoff            = "EVERY"
+    refresh_period_days_for_replica_os_disk = 7
+  }`,
+			},
+		},
+		{
+			name: "dedicated pool with multiple sessions per user",
+			overrides: map[string]string{
+				"user_assignment": `user_assignment = "DEDICATED"`,
+				"session_settings": `session_settings {
+    allow_multiple_sessions_per_user = true
+  }`,
+			},
+			expectError: `allow_multiple_sessions_per_user can only be set when user_assignment is "FLOATING"`,
+		},
+		{
+			name: "dedicated pool deleting machines after logoff",
+			overrides: map[string]string{
+				"user_assignment": `user_assignment = "DEDICATED"`,
+				"session_settings": `session_settings {
+    delete_or_refresh_machine_after_logoff = "DELETE"
+  }`,
+			},
+			expectError: `delete_or_refresh_machine_after_logoff can only be set when user_assignment is "FLOATING"`,
+		},
+		{
+			name: "periodic OS disk refresh without days",
+			overrides: map[string]string{
+				"user_assignment": `user_assignment = "DEDICATED"`,
+				"session_settings": `session_settings {
+    refresh_os_disk_after_logoff = "EVERY"
+  }`,
+			},
+			expectError: `refresh_period_days_for_replica_os_disk must be set`,
+		},
+		{
+			name: "OS disk refresh at size without threshold",
+			overrides: map[string]string{
+				"user_assignment": `user_assignment = "DEDICATED"`,
+				"session_settings": `session_settings {
+    refresh_os_disk_after_logoff = "AT_SIZE"
+  }`,
+			},
+			expectError: `refresh_threshold_percentage_for_replica_os_disk must be set`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHorizonServer(t, nil)
+			testPlan(t, testDesktopPoolAutomatedConfig(srv, tc.overrides)+tc.extraConfig, tc.expectError)
+		})
+	}
+}
+
+func TestValidateBlackoutTime(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{"00:00", true},
+		{"09:30", true},
+		{"19:05", true},
+		{"23:59", true},
+		{"24:00", false},
+		{"23:60", false},
+		{"9:30", false},
+		{"09:30:00", false},
+		{"0930", false},
+		{" 09:30", false},
+		{"", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			_, errs := validateBlackoutTime(tc.value, "start_time")
+			if valid := len(errs) == 0; valid != tc.valid {
+				t.Errorf("validateBlackoutTime(%q) valid = %t, want %t: %v", tc.value, valid, tc.valid, errs)
+			}
+		})
+	}
+}
+
+func TestResourceDesktopPoolAutomatedPlanViewStorageAccelerator(t *testing.T) {
+	vsaSettings := func(days, startTime, endTime string) map[string]string {
+		return map[string]string{"view_storage_accelerator_settings": fmt.Sprintf(`view_storage_accelerator_settings {
+    use_view_storage_accelerator = true
+
+    blackout_times {
+      days       = %s
+      start_time = %q
+      end_time   = %q
+    }
+  }`, days, startTime, endTime)}
+	}
+
+	cases := []struct {
+		name        string
+		overrides   map[string]string
+		expectError string
+	}{
+		{
+			name:      "valid blackout time",
+			overrides: vsaSettings(`["SATURDAY", "SUNDAY"]`, "08:00", "17:30"),
+		},
+		{
+			name:        "abbreviated day",
+			overrides:   vsaSettings(`["SAT"]`, "08:00", "17:30"),
+			expectError: `got SAT`,
+		},
+		{
+			name:        "lower case day",
+			overrides:   vsaSettings(`["MONDAY", "tuesday"]`, "08:00", "17:30"),
+			expectError: `got tuesday`,
+		},
+		{
+			name:        "no days",
+			overrides:   vsaSettings(`[]`, "08:00", "17:30"),
+			expectError: `requires 1 item minimum`,
+		},
+		{
+			name:        "12-hour start time",
+			overrides:   vsaSettings(`["MONDAY"]`, "8:00 AM", "17:30"),
+			expectError: `must be a time in 24-hour HH:MM format`,
+		},
+		{
+			name:        "end time out of range",
+			overrides:   vsaSettings(`["MONDAY"]`, "08:00", "24:00"),
+			expectError: `must be a time in 24-hour HH:MM format`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHorizonServer(t, nil)
+			testPlan(t, testDesktopPoolAutomatedConfig(srv, tc.overrides), tc.expectError)
+		})
+	}
+}
+
+func TestDesktopPoolDeleteFromDisk(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	cases := []struct {
+		name string
+		raw  map[string]interface{}
+		want *bool
+	}{
+		{
+			name: "instant clone without delete settings",
+			raw:  map[string]interface{}{"source": "INSTANT_CLONE"},
+			want: boolPtr(true),
+		},
+		{
+			name: "full clone without delete settings",
+			raw:  map[string]interface{}{"source": "VIRTUAL_CENTER"},
+			want: nil,
+		},
+		{
+			name: "full clone kept on disk",
+			raw: map[string]interface{}{
+				"source":          "VIRTUAL_CENTER",
+				"delete_settings": []interface{}{map[string]interface{}{"delete_from_disk": false}},
+			},
+			want: boolPtr(false),
+		},
+		{
+			name: "full clone with default delete settings",
+			raw: map[string]interface{}{
+				"source":          "VIRTUAL_CENTER",
+				"delete_settings": []interface{}{map[string]interface{}{"force_logoff_sessions": true}},
+			},
+			want: boolPtr(true),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceDesktopPoolAutomated().Schema, tc.raw)
+
+			got := desktopPoolDeleteFromDisk(d)
+			switch {
+			case tc.want == nil && got != nil:
+				t.Errorf("delete_from_disk = %t, want it left to the Horizon API default", *got)
+			case tc.want != nil && got == nil:
+				t.Errorf("delete_from_disk is not set, want %t", *tc.want)
+			case tc.want != nil && *got != *tc.want:
+				t.Errorf("delete_from_disk = %t, want %t", *got, *tc.want)
+			}
+		})
+	}
+}
 
-	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: providerFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccResourceDesktopPoolAutomated,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestMatchResourceAttr(
-						"horizon_desktop_pool_automated.foo", "sample_attribute", regexp.MustCompile("^ba")),
-				),
+// testDesktopPoolAutomatedInfo returns an instant clone desktop pool as returned by the Horizon API.
+func testDesktopPoolAutomatedInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  "pool",
+		"name":                "ic-pool",
+		"type":                "AUTOMATED",
+		"source":              "INSTANT_CLONE",
+		"access_group_id":     "access-group",
+		"vcenter_id":          "vcenter",
+		"naming_method":       "PATTERN",
+		"user_assignment":     "FLOATING",
+		"enable_provisioning": true,
+		"customization_settings": map[string]interface{}{
+			"customization_type":              "CLONE_PREP",
+			"ad_container_rdn":                "OU=VDI",
+			"instant_clone_domain_account_id": "account",
+			"cloneprep_customization_settings": map[string]interface{}{
+				"power_off_script_name": "shutdown.cmd",
+			},
+		},
+		"display_protocol_settings": map[string]interface{}{
+			"default_display_protocol": "BLAST",
+			"max_number_of_monitors":   2,
+		},
+		"nics": []map[string]interface{}{{
+			"network_interface_card_id": "nic",
+			"network_label_assignment_specs": []map[string]interface{}{{
+				"enabled":            true,
+				"max_label_type":     "UNLIMITED",
+				"network_label_name": "VDI",
+			}},
+		}},
+		"pattern_naming_settings": map[string]interface{}{
+			"naming_pattern":         "ic-{n}",
+			"max_number_of_machines": 2,
+		},
+		"provisioning_settings": map[string]interface{}{
+			"datacenter_id":      "datacenter",
+			"host_or_cluster_id": "cluster",
+			"resource_pool_id":   "resource-pool",
+			"vm_folder_id":       "folder",
+			"parent_vm_id":       "parent-vm",
+			"base_snapshot_id":   "snapshot",
+		},
+		"storage_settings": map[string]interface{}{
+			"datastores": []map[string]interface{}{{"datastore_id": "datastore"}},
+		},
+	}
+}
+
+func TestResourceDesktopPoolAutomatedRead(t *testing.T) {
+	specifiedPool := testDesktopPoolAutomatedInfo()
+	delete(specifiedPool, "pattern_naming_settings")
+	specifiedPool["naming_method"] = "SPECIFIED"
+	specifiedPool["specific_naming_settings"] = map[string]interface{}{"num_unassigned_machines_kept_powered_on": 1}
+
+	machines := map[string]http.HandlerFunc{
+		"/rest/inventory/v1/machines": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+				testDesktopPoolMachine("vm-1", "AVAILABLE"),
+				testDesktopPoolMachine("vm-2", "PROVISIONING"),
+				testDesktopPoolMachine("vm-old", "DELETING"),
+			})
+		},
+	}
+
+	testResourceRead(t, resourceDesktopPoolAutomated(), "pool", "/rest/inventory/v5/desktop-pools/pool", machines, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:   "instant clone desktop pool",
+			object: testDesktopPoolAutomatedInfo(),
+			wantID: "pool",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				want := map[string]interface{}{
+					"customization_type":                                         "CLONE_PREP",
+					"clone_prep_settings.0.ad_container_rdn":                     "OU=VDI",
+					"clone_prep_settings.0.power_off_script_name":                "shutdown.cmd",
+					"display_protocol_settings.0.default_display_protocol":       "BLAST",
+					"display_protocol_settings.0.max_number_of_monitors":         2,
+					"nics.0.network_label_assignment_specs.0.network_label_name": "VDI",
+					"pattern_naming_settings.0.naming_pattern":                   "ic-{n}",
+					"pattern_naming_settings.0.max_number_of_machines":           2,
+					"provisioning_settings.0.parent_vm_id":                       "parent-vm",
+					"provisioning_settings.0.base_snapshot_id":                   "snapshot",
+				}
+				for k, v := range want {
+					if got := d.Get(k); got != v {
+						t.Errorf("%s = %v, want %v", k, got, v)
+					}
+				}
+				if datastores := d.Get("storage_settings.0.datastores").(*schema.Set).List(); len(datastores) != 1 || datastores[0].(map[string]interface{})["datastore_id"] != "datastore" {
+					t.Errorf("datastores = %v, want datastore", datastores)
+				}
+				if got := d.Get("sys_prep_settings").([]interface{}); len(got) != 0 {
+					t.Errorf("sys_prep_settings = %v, want none for a CLONE_PREP desktop pool", got)
+				}
 			},
 		},
+		{
+			name: "pushed image",
+			object: func() map[string]interface{} {
+				pool := testDesktopPoolAutomatedInfo()
+				pool["provisioning_status_data"] = map[string]interface{}{
+					"instant_clone_operation":                  "PUSH_IMAGE",
+					"instant_clone_pending_image_parent_vm_id": "parent-vm-2",
+					"instant_clone_pending_image_snapshot_id":  "snapshot-2",
+				}
+				return pool
+			}(),
+			wantID: "pool",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := d.Get("provisioning_settings.0.parent_vm_id"); got != "parent-vm-2" {
+					t.Errorf("parent_vm_id = %v, want the pending image", got)
+				}
+				if got := d.Get("provisioning_settings.0.base_snapshot_id"); got != "snapshot-2" {
+					t.Errorf("base_snapshot_id = %v, want the pending image", got)
+				}
+			},
+		},
+		{
+			name:   "specified names",
+			object: specifiedPool,
+			wantID: "pool",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := sortedStringSet(d.Get("specific_naming_settings.0.specified_names").(*schema.Set)); !reflect.DeepEqual(got, []string{"vm-1", "vm-2"}) {
+					t.Errorf("specified_names = %v, want the machines that are not being deleted", got)
+				}
+				if got := d.Get("pattern_naming_settings").([]interface{}); len(got) != 0 {
+					t.Errorf("pattern_naming_settings = %v, want none for a SPECIFIED desktop pool", got)
+				}
+			},
+		},
+	})
+}
+
+// testDesktopPoolAutomatedState returns the state of the instant clone desktop pool of
+// testDesktopPoolAutomatedSettings with settings replaced by overrides.
+func testDesktopPoolAutomatedState(overrides map[string]interface{}) map[string]interface{} {
+	state := map[string]interface{}{
+		"name":                "ic-pool",
+		"access_group_id":     "access-group",
+		"vcenter_id":          "vcenter",
+		"source":              "INSTANT_CLONE",
+		"naming_method":       "PATTERN",
+		"user_assignment":     "FLOATING",
+		"customization_type":  "CLONE_PREP",
+		"enable_provisioning": true,
+		"enabled":             true,
+		"display_name":        "IC Pool",
+		"clone_prep_settings": []interface{}{map[string]interface{}{
+			"ad_container_rdn":                "OU=VDI",
+			"instant_clone_domain_account_id": "account",
+		}},
+		"pattern_naming_settings": []interface{}{map[string]interface{}{
+			"naming_pattern":         "ic-{n}",
+			"max_number_of_machines": 5,
+		}},
+		"provisioning_settings": []interface{}{map[string]interface{}{
+			"host_or_cluster_id": "cluster",
+			"resource_pool_id":   "resource-pool",
+			"vm_folder_id":       "folder",
+			"parent_vm_id":       "parent-vm",
+			"base_snapshot_id":   "snapshot",
+		}},
+		"storage_settings": []interface{}{map[string]interface{}{
+			"datastores": []interface{}{map[string]interface{}{"datastore_id": "datastore"}},
+		}},
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(state, k)
+			continue
+		}
+		state[k] = v
+	}
+
+	return state
+}
+
+func TestResourceDesktopPoolAutomatedUpdate(t *testing.T) {
+	var spec map[string]interface{}
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/inventory/v1/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				t.Errorf("method = %s, want %s", r.Method, http.MethodPut)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				t.Errorf("error decoding the update spec: %s", err)
+			}
+		},
+		"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, testDesktopPoolAutomatedInfo())
+		},
 	})
+
+	d := testResourceDataUpdate(t, resourceDesktopPoolAutomated(), "pool", testDesktopPoolAutomatedState(nil), testDesktopPoolAutomatedState(map[string]interface{}{
+		"display_name":        "Instant Clones",
+		"description":         "updated",
+		"enable_provisioning": false,
+	}))
+
+	if diags := resourceDesktopPoolUpdate(context.Background(), d, testAPIClient(srv)); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	want := map[string]interface{}{
+		"display_name":        "Instant Clones",
+		"description":         "updated",
+		"enable_provisioning": false,
+		"access_group_id":     "access-group",
+	}
+	for k, v := range want {
+		if spec[k] != v {
+			t.Errorf("%s = %v, want %v", k, spec[k], v)
+		}
+	}
+
+	provisioning, _ := spec["provisioning_settings"].(map[string]interface{})
+	if provisioning["host_or_cluster_id"] != "cluster" || provisioning["resource_pool_id"] != "resource-pool" {
+		t.Errorf("provisioning_settings = %v, want the cluster and resource pool", provisioning)
+	}
+	if _, ok := provisioning["vm_template_id"]; ok {
+		t.Errorf("provisioning_settings = %v, want no VM template for an instant clone desktop pool", provisioning)
+	}
+
+	naming, _ := spec["pattern_naming_settings"].(map[string]interface{})
+	if naming["naming_pattern"] != "ic-{n}" || naming["max_number_of_machines"] != float64(5) {
+		t.Errorf("pattern_naming_settings = %v, want ic-{n} with 5 machines", naming)
+	}
+
+	customization, _ := spec["customization_settings"].(map[string]interface{})
+	if customization["customization_type"] != "CLONE_PREP" || customization["ad_container_rdn"] != "OU=VDI" {
+		t.Errorf("customization_settings = %v, want ClonePrep in OU=VDI", customization)
+	}
+
+	storage, _ := spec["storage_settings"].(map[string]interface{})
+	datastores, _ := storage["datastores"].([]interface{})
+	if len(datastores) != 1 || datastores[0].(map[string]interface{})["datastore_id"] != "datastore" {
+		t.Errorf("datastores = %v, want datastore", datastores)
+	}
+	if _, ok := spec["nics"]; ok {
+		t.Errorf("nics = %v, want unchanged nics left out of the update spec", spec["nics"])
+	}
+}
+
+// testDesktopPoolMachine returns a machine of the desktop pool "pool" as returned by the Horizon API.
+func testDesktopPoolMachine(name, state string) map[string]interface{} {
+	return map[string]interface{}{"id": "machine-" + name, "name": name, "state": state, "desktop_pool_id": "pool"}
+}
+
+func TestResourceDesktopPoolUpdateSpecifiedNames(t *testing.T) {
+	specified := func(names ...interface{}) map[string]interface{} {
+		return testDesktopPoolAutomatedState(map[string]interface{}{
+			"naming_method":           "SPECIFIED",
+			"pattern_naming_settings": nil,
+			"specific_naming_settings": []interface{}{map[string]interface{}{
+				"specified_names": names,
+			}},
+		})
+	}
+
+	cases := []struct {
+		name       string
+		state      map[string]interface{}
+		config     map[string]interface{}
+		failDelete bool
+		wantAdd    []string
+		wantRemove []string
+		wantError  string
+	}{
+		{
+			name:       "add and remove",
+			state:      specified("vm-1", "vm-2"),
+			config:     specified("vm-2", "vm-3"),
+			wantAdd:    []string{"vm-3"},
+			wantRemove: []string{"machine-vm-1"},
+		},
+		{
+			name:    "add a name whose machine is being deleted",
+			state:   specified("vm-1", "vm-2"),
+			config:  specified("vm-1", "vm-2", "vm-old"),
+			wantAdd: []string{"vm-old"},
+		},
+		{
+			name:       "failed delete",
+			state:      specified("vm-1", "vm-2"),
+			config:     specified("vm-2"),
+			failDelete: true,
+			wantRemove: []string{"machine-vm-1"},
+			wantError:  "operation failed for machine-vm-1 with status 409",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var added, removed []string
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/machines": func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodGet {
+						writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+							testDesktopPoolMachine("vm-1", "AVAILABLE"),
+							testDesktopPoolMachine("vm-2", "AVAILABLE"),
+							testDesktopPoolMachine("vm-old", "DELETING"),
+						})
+						return
+					}
+
+					var spec struct {
+						MachineIDs []string `json:"machine_ids"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+						t.Errorf("error decoding the delete spec: %s", err)
+					}
+					removed = spec.MachineIDs
+
+					results := []map[string]interface{}{}
+					for _, id := range spec.MachineIDs {
+						result := map[string]interface{}{"id": id, "status_code": http.StatusNoContent}
+						if tc.failDelete {
+							result["status_code"] = http.StatusConflict
+							result["error_messages"] = []string{"machine is in use"}
+						}
+						results = append(results, result)
+					}
+					writeTestJSON(w, http.StatusOK, results)
+				},
+				"/rest/inventory/v1/desktop-pools/pool/action/add-machines-by-name": func(w http.ResponseWriter, r *http.Request) {
+					var names []map[string]string
+					if err := json.NewDecoder(r.Body).Decode(&names); err != nil {
+						t.Errorf("error decoding the specified names: %s", err)
+					}
+
+					results := []map[string]interface{}{}
+					for _, name := range names {
+						added = append(added, name["name"])
+						results = append(results, map[string]interface{}{"id": name["name"], "status_code": http.StatusOK})
+					}
+					writeTestJSON(w, http.StatusOK, results)
+				},
+			})
+
+			d := testResourceDataUpdate(t, resourceDesktopPoolAutomated(), "pool", tc.state, tc.config)
+			client := testAPIClient(srv).Client
+
+			diags := resourceDesktopPoolUpdateSpecifiedNames(context.Background(), d, &client)
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(added, tc.wantAdd) {
+				t.Errorf("added = %v, want %v", added, tc.wantAdd)
+			}
+			if !reflect.DeepEqual(removed, tc.wantRemove) {
+				t.Errorf("removed = %v, want %v", removed, tc.wantRemove)
+			}
+		})
+	}
+}
+
+func TestResourceDesktopPoolPushImage(t *testing.T) {
+	pollInterval := desktopPoolPollInterval
+	desktopPoolPollInterval = time.Millisecond
+	t.Cleanup(func() { desktopPoolPollInterval = pollInterval })
+
+	newImage := func(pushImageSettings map[string]interface{}) map[string]interface{} {
+		overrides := map[string]interface{}{
+			"provisioning_settings": []interface{}{map[string]interface{}{
+				"host_or_cluster_id": "cluster",
+				"resource_pool_id":   "resource-pool",
+				"vm_folder_id":       "folder",
+				"parent_vm_id":       "parent-vm-2",
+				"base_snapshot_id":   "snapshot-2",
+			}},
+		}
+		if pushImageSettings != nil {
+			overrides["push_image_settings"] = []interface{}{pushImageSettings}
+		}
+		return testDesktopPoolAutomatedState(overrides)
+	}
+
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		// status is the provisioning status data of the desktop pool while the image is pushed.
+		status        map[string]interface{}
+		failSchedule  bool
+		wantSpec      map[string]interface{}
+		wantSummaries []string
+	}{
+		{
+			name:   "default settings",
+			config: newImage(nil),
+			wantSpec: map[string]interface{}{
+				"logoff_policy":       "WAIT_FOR_LOGOFF",
+				"parent_vm_id":        "parent-vm-2",
+				"snapshot_id":         "snapshot-2",
+				"stop_on_first_error": true,
+			},
+		},
+		{
+			name: "scheduled push",
+			config: newImage(map[string]interface{}{
+				"logoff_policy":       "FORCE_LOGOFF",
+				"start_time":          "2026-01-02T03:04:05Z",
+				"stop_on_first_error": false,
+			}),
+			wantSpec: map[string]interface{}{
+				"logoff_policy":       "FORCE_LOGOFF",
+				"parent_vm_id":        "parent-vm-2",
+				"snapshot_id":         "snapshot-2",
+				"start_time":          float64(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli()),
+				"stop_on_first_error": false,
+			},
+		},
+		{
+			name: "image management stream",
+			config: testDesktopPoolAutomatedState(map[string]interface{}{
+				"provisioning_settings": []interface{}{map[string]interface{}{
+					"host_or_cluster_id": "cluster",
+					"resource_pool_id":   "resource-pool",
+					"vm_folder_id":       "folder",
+					"im_stream_id":       "stream",
+					"im_tag_id":          "tag",
+				}},
+			}),
+			wantSpec: map[string]interface{}{
+				"logoff_policy":       "WAIT_FOR_LOGOFF",
+				"im_stream_id":        "stream",
+				"im_tag_id":           "tag",
+				"stop_on_first_error": true,
+			},
+		},
+		{
+			name:   "wait for completion with a failed machine",
+			config: newImage(map[string]interface{}{"wait_for_completion": true}),
+			status: map[string]interface{}{"instant_clone_operation": "NONE"},
+			wantSpec: map[string]interface{}{
+				"logoff_policy":       "WAIT_FOR_LOGOFF",
+				"parent_vm_id":        "parent-vm-2",
+				"snapshot_id":         "snapshot-2",
+				"stop_on_first_error": true,
+			},
+			wantSummaries: []string{"machine ic-2 is in state PROVISIONING_ERROR"},
+		},
+		{
+			name:   "failed push",
+			config: newImage(map[string]interface{}{"wait_for_completion": true}),
+			status: map[string]interface{}{
+				"instant_clone_operation":           "PUSH_IMAGE",
+				"instant_clone_pending_image_state": "FAILED",
+				"instant_clone_pending_image_error": "snapshot not found",
+			},
+			wantSpec: map[string]interface{}{
+				"logoff_policy":       "WAIT_FOR_LOGOFF",
+				"parent_vm_id":        "parent-vm-2",
+				"snapshot_id":         "snapshot-2",
+				"stop_on_first_error": true,
+			},
+			wantSummaries: []string{"push image operation failed", "machine ic-2 is in state PROVISIONING_ERROR"},
+		},
+		{
+			name:          "rejected push",
+			config:        newImage(nil),
+			failSchedule:  true,
+			wantSummaries: []string{"Horizon API error"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var spec map[string]interface{}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/desktop-pools/pool/action/schedule-push-image": func(w http.ResponseWriter, r *http.Request) {
+					if tc.failSchedule {
+						writeTestJSON(w, http.StatusBadRequest, map[string]string{"error_key": "invalid.snapshot", "error_message": "Invalid snapshot"})
+						return
+					}
+					if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+						t.Errorf("error decoding the push image spec: %s", err)
+					}
+					w.WriteHeader(http.StatusNoContent)
+				},
+				"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					pool := testDesktopPoolAutomatedInfo()
+					pool["provisioning_status_data"] = tc.status
+					writeTestJSON(w, http.StatusOK, pool)
+				},
+				"/rest/inventory/v1/machines": func(w http.ResponseWriter, r *http.Request) {
+					failed := testDesktopPoolMachine("ic-2", "PROVISIONING_ERROR")
+					failed["managed_machine_data"] = map[string]interface{}{"clone_error_message": "customization failed"}
+					writeTestJSON(w, http.StatusOK, []map[string]interface{}{testDesktopPoolMachine("ic-1", "AVAILABLE"), failed})
+				},
+			})
+
+			d := testResourceDataUpdate(t, resourceDesktopPoolAutomated(), "pool", testDesktopPoolAutomatedState(nil), tc.config)
+			client := testAPIClient(srv).Client
+
+			diags := resourceDesktopPoolPushImage(context.Background(), d, &client)
+
+			summaries := []string{}
+			for _, diag := range diags {
+				summaries = append(summaries, diag.Summary)
+			}
+			if len(summaries) != len(tc.wantSummaries) {
+				t.Fatalf("diagnostics = %v, want %v", summaries, tc.wantSummaries)
+			}
+			for i, want := range tc.wantSummaries {
+				if !strings.Contains(summaries[i], want) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, summaries[i], want)
+				}
+			}
+			if len(diags) > 0 && diags[0].Summary == "push image operation failed" && diags[0].Detail != "snapshot not found" {
+				t.Errorf("push image error = %q, want the pending image error", diags[0].Detail)
+			}
+
+			if tc.wantSpec != nil && !reflect.DeepEqual(spec, tc.wantSpec) {
+				t.Errorf("push image spec = %v, want %v", spec, tc.wantSpec)
+			}
+		})
+	}
+}
+
+func TestResourceDesktopPoolWaitForProvisioning(t *testing.T) {
+	pollInterval := desktopPoolPollInterval
+	desktopPoolPollInterval = time.Millisecond
+	t.Cleanup(func() { desktopPoolPollInterval = pollInterval })
+
+	failed := testDesktopPoolMachine("ic-2", "PROVISIONING_ERROR")
+	failed["managed_machine_data"] = map[string]interface{}{"clone_error_message": "customization failed"}
+
+	cases := []struct {
+		name      string
+		condition string
+		machines  []map[string]interface{}
+		// status is the provisioning status data of the desktop pool, which disables provisioning if it has an error.
+		status        map[string]interface{}
+		wantSummaries []string
+	}{
+		{
+			name:      "machines available",
+			condition: "MACHINES_AVAILABLE",
+			machines:  []map[string]interface{}{testDesktopPoolMachine("ic-1", "AVAILABLE"), testDesktopPoolMachine("ic-2", "PROVISIONING")},
+		},
+		{
+			name:      "provisioning complete",
+			condition: "PROVISIONING_COMPLETE",
+			machines:  []map[string]interface{}{testDesktopPoolMachine("ic-1", "AVAILABLE"), testDesktopPoolMachine("ic-2", "AVAILABLE")},
+		},
+		{
+			name:          "provisioning in progress",
+			condition:     "PROVISIONING_COMPLETE",
+			machines:      []map[string]interface{}{testDesktopPoolMachine("ic-1", "AVAILABLE"), testDesktopPoolMachine("ic-2", "CUSTOMIZING")},
+			wantSummaries: []string{"timeout while waiting for state"},
+		},
+		{
+			name:          "deleting machines are not counted",
+			condition:     "PROVISIONING_COMPLETE",
+			machines:      []map[string]interface{}{testDesktopPoolMachine("ic-1", "AVAILABLE"), testDesktopPoolMachine("ic-2", "DELETING")},
+			wantSummaries: []string{"timeout while waiting for state"},
+		},
+		{
+			name:          "provisioning stopped on error",
+			condition:     "MACHINES_AVAILABLE",
+			machines:      []map[string]interface{}{testDesktopPoolMachine("ic-1", "PROVISIONING"), failed},
+			status:        map[string]interface{}{"last_provisioning_error": "out of disk space"},
+			wantSummaries: []string{"provisioning was disabled because of an error", "machine ic-2 is in state PROVISIONING_ERROR"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					pool := testDesktopPoolAutomatedInfo()
+					pool["enable_provisioning"] = tc.status == nil
+					pool["provisioning_status_data"] = tc.status
+					writeTestJSON(w, http.StatusOK, pool)
+				},
+				"/rest/inventory/v1/machines": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, tc.machines)
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceDesktopPoolAutomated().Schema, testDesktopPoolAutomatedState(map[string]interface{}{
+				"pattern_naming_settings": []interface{}{map[string]interface{}{
+					"naming_pattern":         "ic-{n}",
+					"max_number_of_machines": 2,
+				}},
+				"wait_for_provisioning": []interface{}{map[string]interface{}{"condition": tc.condition}},
+			}))
+			d.SetId("pool")
+			client := testAPIClient(srv).Client
+
+			diags := resourceDesktopPoolWaitForProvisioning(context.Background(), d, &client, 100*time.Millisecond)
+
+			summaries := []string{}
+			for _, diag := range diags {
+				summaries = append(summaries, diag.Summary)
+			}
+			if len(summaries) != len(tc.wantSummaries) {
+				t.Fatalf("diagnostics = %v, want %v", summaries, tc.wantSummaries)
+			}
+			for i, want := range tc.wantSummaries {
+				if !strings.Contains(summaries[i], want) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, summaries[i], want)
+				}
+			}
+			if len(diags) > 1 && diags[0].Detail != "out of disk space" {
+				t.Errorf("provisioning error = %q, want the last provisioning error", diags[0].Detail)
+			}
+			if len(diags) > 1 && diags[1].Detail != "customization failed" {
+				t.Errorf("machine error = %q, want the clone error of the machine", diags[1].Detail)
+			}
+		})
+	}
+}
+
+func TestResourceDesktopPoolUpdateWaitForProvisioning(t *testing.T) {
+	pollInterval := desktopPoolPollInterval
+	desktopPoolPollInterval = time.Millisecond
+	t.Cleanup(func() { desktopPoolPollInterval = pollInterval })
+
+	waiting := func(overrides map[string]interface{}) map[string]interface{} {
+		overrides["wait_for_provisioning"] = []interface{}{map[string]interface{}{"condition": "MACHINES_AVAILABLE"}}
+		return testDesktopPoolAutomatedState(overrides)
+	}
+
+	cases := []struct {
+		name     string
+		state    map[string]interface{}
+		config   map[string]interface{}
+		wantWait bool
+	}{
+		{
+			name:   "display name changed",
+			state:  waiting(map[string]interface{}{}),
+			config: waiting(map[string]interface{}{"display_name": "Instant Clones"}),
+		},
+		{
+			name:  "machines added",
+			state: waiting(map[string]interface{}{}),
+			config: waiting(map[string]interface{}{
+				"pattern_naming_settings": []interface{}{map[string]interface{}{
+					"naming_pattern":         "ic-{n}",
+					"max_number_of_machines": 10,
+				}},
+			}),
+			wantWait: true,
+		},
+		{
+			name:     "wait added",
+			state:    testDesktopPoolAutomatedState(nil),
+			config:   waiting(map[string]interface{}{}),
+			wantWait: true,
+		},
+		{
+			name:   "provisioning disabled",
+			state:  waiting(map[string]interface{}{}),
+			config: waiting(map[string]interface{}{"enable_provisioning": false}),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			machinesListed := false
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				},
+				"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, testDesktopPoolAutomatedInfo())
+				},
+				"/rest/inventory/v1/machines": func(w http.ResponseWriter, r *http.Request) {
+					machinesListed = true
+					writeTestJSON(w, http.StatusOK, []map[string]interface{}{testDesktopPoolMachine("ic-1", "AVAILABLE")})
+				},
+			})
+
+			d := testResourceDataUpdate(t, resourceDesktopPoolAutomated(), "pool", tc.state, tc.config)
+
+			if diags := resourceDesktopPoolUpdate(context.Background(), d, testAPIClient(srv)); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if machinesListed != tc.wantWait {
+				t.Errorf("waited for provisioning = %t, want %t", machinesListed, tc.wantWait)
+			}
+		})
+	}
+}
+
+func TestFlattenDesktopPoolSessionSettings(t *testing.T) {
+	cases := []struct {
+		name           string
+		userAssignment string
+		// settings are the session settings returned by the Horizon API, which leaves out those that do not apply.
+		settings string
+	}{
+		{
+			name:           "floating desktop pool",
+			userAssignment: "FLOATING",
+			settings: `{
+				"allow_multiple_sessions_per_user": false,
+				"allow_users_to_reset_machines": true,
+				"delete_or_refresh_machine_after_logoff": "NEVER",
+				"disconnected_session_timeout_policy": "NEVER",
+				"power_policy": "TAKE_NO_POWER_ACTION"
+			}`,
+		},
+		{
+			name:           "dedicated desktop pool",
+			userAssignment: "DEDICATED",
+			settings: `{
+				"allow_users_to_reset_machines": true,
+				"disconnected_session_timeout_policy": "NEVER",
+				"power_policy": "ALWAYS_POWERED_ON",
+				"refresh_os_disk_after_logoff": "NEVER"
+			}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var settings gohorizon.DesktopPoolSessionSettingsV3
+			if err := json.Unmarshal([]byte(tc.settings), &settings); err != nil {
+				t.Fatalf("error decoding the session settings: %s", err)
+			}
+
+			r := resourceDesktopPoolAutomated()
+			config := testDesktopPoolAutomatedState(map[string]interface{}{
+				"user_assignment":  tc.userAssignment,
+				"session_settings": []interface{}{map[string]interface{}{"allow_users_to_reset_machines": true}},
+			})
+
+			d := schema.TestResourceDataRaw(t, r.Schema, config)
+			d.SetId("pool")
+			if err := d.Set("session_settings", flattenDesktopPoolSessionSettings(&settings)); err != nil {
+				t.Fatalf("error setting session_settings: %s", err)
+			}
+
+			diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), nil)
+			if err != nil {
+				t.Fatalf("error computing the diff: %s", err)
+			}
+			if diff == nil {
+				return
+			}
+			for k, attr := range diff.Attributes {
+				if strings.HasPrefix(k, "session_settings.") {
+					t.Errorf("%s changes from %q to %q, want the defaults of settings left out by the Horizon API", k, attr.Old, attr.New)
+				}
+			}
+		})
+	}
 }
 
-const testAccResourceDesktopPoolAutomated = `
-resource "scaffolding_resource" "foo" {
-  sample_attribute = "bar"
+func TestResourceDesktopPoolAutomatedDelete(t *testing.T) {
+	pollInterval := desktopPoolDeletePollInterval
+	desktopPoolDeletePollInterval = time.Millisecond
+	t.Cleanup(func() { desktopPoolDeletePollInterval = pollInterval })
+
+	cases := []struct {
+		name           string
+		deleteSettings map[string]interface{}
+		wantCalls      []string
+	}{
+		{
+			name:      "default delete settings",
+			wantCalls: []string{"DELETE desktop pool"},
+		},
+		{
+			name:           "force logoff",
+			deleteSettings: map[string]interface{}{"force_logoff_sessions": true},
+			wantCalls:      []string{"PUT desktop pool", "GET machines", "DELETE machines", "DELETE desktop pool"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			var updateSpec, machineDeleteSpec map[string]interface{}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, r.Method+" desktop pool")
+					if r.Method == http.MethodPut {
+						if err := json.NewDecoder(r.Body).Decode(&updateSpec); err != nil {
+							t.Errorf("error decoding the update spec: %s", err)
+						}
+					}
+					w.WriteHeader(http.StatusNoContent)
+				},
+				"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusNotFound, map[string]string{"error_key": "not.found", "error_message": "Object not found"})
+				},
+				"/rest/inventory/v1/machines": func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, r.Method+" machines")
+					if r.Method == http.MethodGet {
+						writeTestJSON(w, http.StatusOK, []map[string]interface{}{
+							testDesktopPoolMachine("ic-1", "CONNECTED"),
+							testDesktopPoolMachine("ic-2", "DELETING"),
+						})
+						return
+					}
+
+					if err := json.NewDecoder(r.Body).Decode(&machineDeleteSpec); err != nil {
+						t.Errorf("error decoding the machine delete spec: %s", err)
+					}
+					writeTestJSON(w, http.StatusOK, []map[string]interface{}{{"id": "machine-ic-1", "status_code": http.StatusNoContent}})
+				},
+			})
+
+			state := testDesktopPoolAutomatedState(nil)
+			if tc.deleteSettings != nil {
+				state["delete_settings"] = []interface{}{tc.deleteSettings}
+			}
+			d := schema.TestResourceDataRaw(t, resourceDesktopPoolAutomated().Schema, state)
+			d.SetId("pool")
+
+			if diags := resourceDesktopPoolAutomatedDelete(context.Background(), d, testAPIClient(srv)); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(calls, tc.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tc.wantCalls)
+			}
+			if d.Id() != "" {
+				t.Errorf("ID = %q, want the deleted desktop pool removed from state", d.Id())
+			}
+			if updateSpec != nil && updateSpec["enable_provisioning"] != false {
+				t.Errorf("enable_provisioning = %v, want provisioning disabled before the machines are deleted", updateSpec["enable_provisioning"])
+			}
+			if machineDeleteSpec != nil {
+				if ids := machineDeleteSpec["machine_ids"]; !reflect.DeepEqual(ids, []interface{}{"machine-ic-1"}) {
+					t.Errorf("machine_ids = %v, want the machines that are not being deleted", ids)
+				}
+				deleteData, _ := machineDeleteSpec["machine_delete_data"].(map[string]interface{})
+				if deleteData["force_logoff_session"] != true || deleteData["delete_from_disk"] != true {
+					t.Errorf("machine_delete_data = %v, want the sessions logged off and the VMs deleted from disk", deleteData)
+				}
+			}
+		})
+	}
 }
-`
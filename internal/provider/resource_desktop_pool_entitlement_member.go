@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceDesktopPoolEntitlementMember() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for managing a single AD user or group entitled to a desktop pool in Horizon. Unlike horizon_desktop_pool_entitlements, this resource does not modify any of the other entitlements of the desktop pool. The ID of this resource is in the form `<pool_id>/<ad_user_or_group_id>`, which is also the format used to import it.",
+
+		CreateContext: resourceDesktopPoolEntitlementMemberCreate,
+		ReadContext:   resourceDesktopPoolEntitlementMemberRead,
+		DeleteContext: resourceDesktopPoolEntitlementMemberDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"ad_user_or_group_id": {
+				Description: "SID of the AD user or group to entitle to the desktop pool.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"pool_id": {
+				Description: "Unique ID representing the desktop pool.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+// parseDesktopPoolEntitlementMemberID splits a horizon_desktop_pool_entitlement_member ID into the desktop pool ID and the SID.
+func parseDesktopPoolEntitlementMemberID(id string) (string, string, diag.Diagnostics) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", diag.Errorf("unexpected ID %q, expected <pool_id>/<ad_user_or_group_id>", id)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func resourceDesktopPoolEntitlementMemberCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	poolID := d.Get("pool_id").(string)
+	adID := d.Get("ad_user_or_group_id").(string)
+
+	bodyElem := gohorizon.NewEntitlementSpec()
+	bodyElem.Id = &poolID
+	bodyElem.AdUserOrGroupIds = &[]string{adID}
+	body := []gohorizon.EntitlementSpec{*bodyElem}
+
+	results, resp, err := client.EntitlementsApi.BulkCreateDesktopPoolEntitlements(ctx).Body(body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
+	}
+
+	d.SetId(poolID + "/" + adID)
+
+	return resourceDesktopPoolEntitlementMemberRead(ctx, d, meta)
+}
+
+func resourceDesktopPoolEntitlementMemberRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	poolID, adID, diags := parseDesktopPoolEntitlementMemberID(d.Id())
+	if diags != nil {
+		return diags
+	}
+
+	entitlement, resp, err := client.EntitlementsApi.GetDesktopPoolEntitlements(ctx, poolID).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the desktop pool was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	// the SID was removed from the pool outside of Terraform
+	found := false
+	for _, id := range entitlement.GetAdUserOrGroupIds() {
+		if id == adID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("pool_id", poolID)
+	d.Set("ad_user_or_group_id", adID)
+
+	return nil
+}
+
+func resourceDesktopPoolEntitlementMemberDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	poolID := d.Get("pool_id").(string)
+	adID := d.Get("ad_user_or_group_id").(string)
+
+	bodyElem := gohorizon.NewEntitlementSpec()
+	bodyElem.Id = &poolID
+	bodyElem.AdUserOrGroupIds = &[]string{adID}
+	body := []gohorizon.EntitlementSpec{*bodyElem}
+
+	results, resp, err := client.EntitlementsApi.BulkDeleteDesktopPoolEntitlements(ctx).Body(body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseDesktopPoolEntitlementMemberID(t *testing.T) {
+	cases := []struct {
+		id       string
+		wantPool string
+		wantSID  string
+		wantErr  bool
+	}{
+		{"pool/S-1-5-21-1", "pool", "S-1-5-21-1", false},
+		{"pool", "", "", true},
+		{"pool/", "", "", true},
+		{"/S-1-5-21-1", "", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.id, func(t *testing.T) {
+			poolID, sid, diags := parseDesktopPoolEntitlementMemberID(tc.id)
+			if diags.HasError() != tc.wantErr {
+				t.Fatalf("parseDesktopPoolEntitlementMemberID(%q) diagnostics = %#v, want error %t", tc.id, diags, tc.wantErr)
+			}
+			if poolID != tc.wantPool || sid != tc.wantSID {
+				t.Errorf("parseDesktopPoolEntitlementMemberID(%q) = %q, %q, want %q, %q", tc.id, poolID, sid, tc.wantPool, tc.wantSID)
+			}
+		})
+	}
+}
+
+func TestResourceDesktopPoolEntitlementMemberCreate(t *testing.T) {
+	cases := []struct {
+		name         string
+		failing      map[string]bool
+		wantError    string
+		wantID       string
+		wantEntitled []string
+	}{
+		{
+			name:         "success",
+			wantID:       "pool/S-1-5-21-2",
+			wantEntitled: []string{"S-1-5-21-1", "S-1-5-21-2"},
+		},
+		{
+			name:         "failed user",
+			failing:      map[string]bool{"S-1-5-21-2": true},
+			wantError:    "entitlement of S-1-5-21-2 to pool failed with status 404",
+			wantID:       "",
+			wantEntitled: []string{"S-1-5-21-1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entitled := []string{"S-1-5-21-1"}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/entitlements/v1/desktop-pools": testEntitlementsHandler(t, &entitled, tc.failing),
+				"/rest/entitlements/v1/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "pool", "ad_user_or_group_ids": entitled})
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceDesktopPoolEntitlementMember().Schema, map[string]interface{}{
+				"pool_id":             "pool",
+				"ad_user_or_group_id": "S-1-5-21-2",
+			})
+
+			diags := resourceDesktopPoolEntitlementMemberCreate(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if d.Id() != tc.wantID {
+				t.Errorf("ID = %q, want %q", d.Id(), tc.wantID)
+			}
+			if !reflect.DeepEqual(entitled, tc.wantEntitled) {
+				t.Errorf("entitled = %v, want %v", entitled, tc.wantEntitled)
+			}
+		})
+	}
+}
+
+func TestResourceDesktopPoolEntitlementMemberRead(t *testing.T) {
+	entitlements := map[string]interface{}{"id": "pool", "ad_user_or_group_ids": []string{"S-1-5-21-1", "S-1-5-21-2"}}
+
+	testResourceRead(t, resourceDesktopPoolEntitlementMember(), "pool/S-1-5-21-1", "/rest/entitlements/v1/desktop-pools/pool", nil, []testReadCase{
+		{
+			name:   "entitled",
+			object: entitlements,
+			wantID: "pool/S-1-5-21-1",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := d.Get("pool_id"); got != "pool" {
+					t.Errorf("pool_id = %v, want %q", got, "pool")
+				}
+			},
+		},
+		{
+			name:   "removed outside of Terraform",
+			id:     "pool/S-1-5-21-3",
+			object: entitlements,
+			wantID: "",
+		},
+		{
+			name:   "desktop pool deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:      "invalid ID",
+			id:        "S-1-5-21-1",
+			object:    entitlements,
+			wantID:    "S-1-5-21-1",
+			wantError: "unexpected ID",
+		},
+	})
+}
+
+func TestResourceDesktopPoolEntitlementMemberDelete(t *testing.T) {
+	entitled := []string{"S-1-5-21-1", "S-1-5-21-2"}
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/entitlements/v1/desktop-pools": testEntitlementsHandler(t, &entitled, map[string]bool{"S-1-5-21-2": true}),
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDesktopPoolEntitlementMember().Schema, map[string]interface{}{
+		"pool_id":             "pool",
+		"ad_user_or_group_id": "S-1-5-21-2",
+	})
+	d.SetId("pool/S-1-5-21-2")
+
+	diags := resourceDesktopPoolEntitlementMemberDelete(context.Background(), d, testAPIClient(srv))
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "entitlement of S-1-5-21-2 to pool failed") {
+		t.Fatalf("diagnostics = %#v, want the failed removal", diags)
+	}
+}
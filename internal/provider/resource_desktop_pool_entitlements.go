@@ -10,7 +10,7 @@ import (
 
 func resourceDesktopPoolEntitlements() *schema.Resource {
 	return &schema.Resource{
-		Description: "Resource for managing desktop pool entitlements in Horizon.",
+		Description: "Resource for managing desktop pool entitlements in Horizon. This resource is authoritative for the entitlements of the desktop pool and will remove any AD users or groups not listed in ad_user_or_group_ids, so it should not be used together with horizon_desktop_pool_entitlement_member on the same desktop pool.",
 
 		CreateContext: resourceDesktopPoolEntitlementsCreate,
 		ReadContext:   resourceDesktopPoolEntitlementsRead,
@@ -57,9 +57,12 @@ func resourceDesktopPoolEntitlementsCreate(ctx context.Context, d *schema.Resour
 	bodyElem.AdUserOrGroupIds = &adIDsRaw
 	body := []gohorizon.EntitlementSpec{*bodyElem}
 
-	_, _, err := client.EntitlementsApi.BulkCreateDesktopPoolEntitlements(ctx).Body(body).Execute()
+	results, resp, err := client.EntitlementsApi.BulkCreateDesktopPoolEntitlements(ctx).Body(body).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
+	}
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
 	}
 
 	d.SetId(poolID)
@@ -72,9 +75,9 @@ func resourceDesktopPoolEntitlementsRead(ctx context.Context, d *schema.Resource
 
 	poolID := d.Id()
 
-	entitlement, _, err := client.EntitlementsApi.GetDesktopPoolEntitlements(ctx, poolID).Execute()
+	entitlement, resp, err := client.EntitlementsApi.GetDesktopPoolEntitlements(ctx, poolID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
 	d.Set("pool_id", poolID)
@@ -95,47 +98,24 @@ func resourceDesktopPoolEntitlementsUpdate(ctx context.Context, d *schema.Resour
 		adIDsRaw = append(adIDsRaw, adIDstr)
 	}
 
-	currentADIDs, _, err := client.EntitlementsApi.GetDesktopPoolEntitlements(ctx, poolID).Execute()
+	currentADIDs, resp, err := client.EntitlementsApi.GetDesktopPoolEntitlements(ctx, poolID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
 
-	removeList := []string{}
-	for _, currentADID := range *currentADIDs.AdUserOrGroupIds {
-		found := false
-		for _, adID := range adIDsRaw {
-			if currentADID == adID {
-				found = true
-				break
-			}
-		}
-		if !found {
-			removeList = append(removeList, currentADID)
-		}
-	}
-
-	addList := []string{}
-	for _, adID := range adIDsRaw {
-		found := false
-		for _, currentADID := range *currentADIDs.AdUserOrGroupIds {
-			if adID == currentADID {
-				found = true
-				break
-			}
-		}
-		if !found {
-			addList = append(addList, adID)
-		}
-	}
+	addList, removeList := diffStringSets(currentADIDs.GetAdUserOrGroupIds(), adIDsRaw)
 
 	if len(removeList) > 0 {
 		removeBodyElem := gohorizon.NewEntitlementSpec()
 		removeBodyElem.Id = &poolID
 		removeBodyElem.AdUserOrGroupIds = &removeList
 		removeBody := []gohorizon.EntitlementSpec{*removeBodyElem}
-		_, _, err := client.EntitlementsApi.BulkDeleteDesktopPoolEntitlements(ctx).Body(removeBody).Execute()
+		results, resp, err := client.EntitlementsApi.BulkDeleteDesktopPoolEntitlements(ctx).Body(removeBody).Execute()
 		if err != nil {
-			return diag.FromErr(err)
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkEntitlementResponseErrors(results); diags != nil {
+			return diags
 		}
 	}
 
@@ -144,9 +124,12 @@ func resourceDesktopPoolEntitlementsUpdate(ctx context.Context, d *schema.Resour
 		addBodyElem.Id = &poolID
 		addBodyElem.AdUserOrGroupIds = &addList
 		addBody := []gohorizon.EntitlementSpec{*addBodyElem}
-		_, _, err := client.EntitlementsApi.BulkCreateDesktopPoolEntitlements(ctx).Body(addBody).Execute()
+		results, resp, err := client.EntitlementsApi.BulkCreateDesktopPoolEntitlements(ctx).Body(addBody).Execute()
 		if err != nil {
-			return diag.FromErr(err)
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkEntitlementResponseErrors(results); diags != nil {
+			return diags
 		}
 	}
 
@@ -161,9 +144,14 @@ func resourceDesktopPoolEntitlementsDelete(ctx context.Context, d *schema.Resour
 	bodyElem := gohorizon.NewEntitlementSpec()
 	bodyElem.Id = &poolID
 	body := []gohorizon.EntitlementSpec{*bodyElem}
-	_, _, err := client.EntitlementsApi.BulkDeleteDesktopPoolEntitlements(ctx).Body(body).Execute()
+	results, resp, err := client.EntitlementsApi.BulkDeleteDesktopPoolEntitlements(ctx).Body(body).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return returnResponseErr(resp, err)
 	}
+
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
+	}
+
 	return nil
 }
@@ -1,32 +1,123 @@
 package provider
 
 import (
-	"regexp"
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-func TestAccResourceDesktopPoolEntitlement(t *testing.T) {
-	t.Skip("resource not yet implemented, remove this once you add your own code")
-
-	resource.UnitTest(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheck(t) },
-		ProviderFactories: providerFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccResourceDesktopPoolEntitlement,
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestMatchResourceAttr(
-						"horizon_desktop_pool_automated.foo", "sample_attribute", regexp.MustCompile("^ba")),
-				),
-			},
+func TestResourceDesktopPoolEntitlementsCreate(t *testing.T) {
+	cases := []struct {
+		name         string
+		failing      map[string]bool
+		wantError    string
+		wantEntitled []string
+	}{
+		{
+			name:         "success",
+			wantEntitled: []string{"S-1-5-21-1", "S-1-5-21-2"},
 		},
-	})
+		{
+			name:         "failed group",
+			failing:      map[string]bool{"S-1-5-21-2": true},
+			wantError:    "entitlement of S-1-5-21-2 to pool failed with status 404",
+			wantEntitled: []string{"S-1-5-21-1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entitled := []string{}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/entitlements/v1/desktop-pools": testEntitlementsHandler(t, &entitled, tc.failing),
+				"/rest/entitlements/v1/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "pool", "ad_user_or_group_ids": entitled})
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceDesktopPoolEntitlements().Schema, map[string]interface{}{
+				"pool_id":              "pool",
+				"ad_user_or_group_ids": []interface{}{"S-1-5-21-1", "S-1-5-21-2"},
+			})
+
+			diags := resourceDesktopPoolEntitlementsCreate(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+				if d.Id() != "" {
+					t.Errorf("ID = %q, want the failed entitlements kept out of state", d.Id())
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(entitled, tc.wantEntitled) {
+				t.Errorf("entitled = %v, want %v", entitled, tc.wantEntitled)
+			}
+		})
+	}
 }
 
-const testAccResourceDesktopPoolEntitlement = `
-resource "scaffolding_resource" "foo" {
-  sample_attribute = "bar"
+func TestResourceDesktopPoolEntitlementsUpdate(t *testing.T) {
+	cases := []struct {
+		name         string
+		failing      map[string]bool
+		wantError    string
+		wantEntitled []string
+	}{
+		{
+			name:         "success",
+			wantEntitled: []string{"S-1-5-21-2", "S-1-5-21-3"},
+		},
+		{
+			name:         "failed removal",
+			failing:      map[string]bool{"S-1-5-21-1": true},
+			wantError:    "entitlement of S-1-5-21-1 to pool failed with status 404",
+			wantEntitled: []string{"S-1-5-21-1", "S-1-5-21-2"},
+		},
+		{
+			name:         "failed addition",
+			failing:      map[string]bool{"S-1-5-21-3": true},
+			wantError:    "entitlement of S-1-5-21-3 to pool failed with status 404",
+			wantEntitled: []string{"S-1-5-21-2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entitled := []string{"S-1-5-21-1", "S-1-5-21-2"}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/entitlements/v1/desktop-pools": testEntitlementsHandler(t, &entitled, tc.failing),
+				"/rest/entitlements/v1/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "pool", "ad_user_or_group_ids": entitled})
+				},
+			})
+
+			d := testResourceDataUpdate(t, resourceDesktopPoolEntitlements(), "pool", map[string]interface{}{
+				"pool_id":              "pool",
+				"ad_user_or_group_ids": []interface{}{"S-1-5-21-1", "S-1-5-21-2"},
+			}, map[string]interface{}{
+				"pool_id":              "pool",
+				"ad_user_or_group_ids": []interface{}{"S-1-5-21-2", "S-1-5-21-3"},
+			})
+
+			diags := resourceDesktopPoolEntitlementsUpdate(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(entitled, tc.wantEntitled) {
+				t.Errorf("entitled = %v, want %v", entitled, tc.wantEntitled)
+			}
+		})
+	}
 }
-`
@@ -0,0 +1,580 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceDesktopPoolManual() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage Horizon Manual Desktop Pools made up of existing vCenter virtual machines or unmanaged physical machines.",
+
+		CreateContext: resourceDesktopPoolManualCreate,
+		ReadContext:   resourceDesktopPoolManualRead,
+		UpdateContext: resourceDesktopPoolManualUpdate,
+		DeleteContext: resourceDesktopPoolDelete,
+
+		CustomizeDiff: customdiff.All(
+			resourceDesktopPoolManualSourceCustomizeDiff,
+			resourceDesktopPoolUserAssignmentCustomizeDiff,
+			resourceDesktopPoolDisplayProtocolCustomizeDiff,
+		),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"access_group_id": {
+				Description: "Access groups can organize the entities such as desktop pools in the organization. They can also be used for delegated administration.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Name of the Desktop Pool. This property must contain only alphanumerics, underscores, and dashes.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[A-Za-z0-9_-]+$`),
+					"name must contain only alphanumerics, underscores, and dashes",
+				),
+			},
+			"source": {
+				Description:  "Source of the Machines in this Desktop Pool. VIRTUAL_CENTER: The Desktop Pool is made up of existing vCenter virtual machines. UNMANAGED: The Desktop Pool is made up of non-vCenter machines such as physical computers, blade PCs and non-vCenter servers.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"VIRTUAL_CENTER", "UNMANAGED"}, false),
+			},
+			"user_assignment": {
+				Description:  "User assignment scheme. DEDICATED: With dedicated assignment, a user returns to the same machine at each session. FLOATING: With floating assignment, a user may return to one of the available machines for the next session.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"DEDICATED", "FLOATING"}, false),
+			},
+			"vcenter_id": {
+				Description: "ID of the virtual center server. This is required when source is VIRTUAL_CENTER and must not be set when source is UNMANAGED.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"machine_ids": {
+				Description:  "IDs of the machines in the desktop pool. When source is VIRTUAL_CENTER these are the IDs of vCenter virtual machines. When source is UNMANAGED these are the IDs of registered physical machines.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"machine_ids", "vm_names"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"vm_names": {
+				Description:  "Names of the vCenter virtual machines in the desktop pool. This can only be used when source is VIRTUAL_CENTER.",
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"machine_ids", "vm_names"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"allow_multiple_user_assignments": {
+				Description: "Only applies to manual desktop pools with dedicated user assignment. Whether assignment of multiple users to a single machine is allowed. If this is true then automatic_user_assignment should be false.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"automatic_user_assignment": {
+				Description: "Automatic assignment of a user the first time they access the machine. This property is applicable if user_assignment is set to DEDICATED with default value as true.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+			},
+			"category_folder_name": {
+				Description: "Name of the category folder in the user's OS containing a shortcut to the desktop pool. Will be unset if the desktop does not belong to a category.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"cs_restriction_tags": {
+				Description: "List of Connection server restriction tags to which the access to the desktop pool is restricted. If this property is not set it indicates that desktop pool can be accessed from any connection server.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"description": {
+				Description: "Description of the desktop pool.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"display_assigned_machine_name": {
+				Description: "Applicable To: Dedicated desktop pools. Indicates whether users should see the hostname of the machine assigned to them instead of display_name when they connect using Horizon Client.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"display_machine_alias": {
+				Description: "Applicable To: Dedicated desktop pools. Indicates whether users should see the machine alias of the machine assigned to them instead of display_name when they connect using Horizon Client.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"display_name": {
+				Description: "Display name of the desktop pool. If the display name is left blank, it defaults to name.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"display_protocol_settings": desktopPoolDisplayProtocolSettingsSchema(),
+			"enable_client_restrictions": {
+				Description: "Client restrictions to be applied to the desktop pool.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"enabled": {
+				Description: "Indicates whether the desktop pool is enabled for brokering.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"session_type": {
+				Description:  "Supported session types for this desktop pool. If this property is set to APPLICATION then this desktop pool can be used for application pool creation. This will be useful when the machines in the pool support application remoting.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"DESKTOP", "APPLICATION", "DESKTOP_AND_APPLICATION"}, false),
+				Default:      "DESKTOP",
+			},
+			"shortcut_locations_v2": {
+				Description: "Locations of the category folder in the user's OS containing a shortcut to the desktop pool. This is required if the category_folder_name is set.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"START_MENU", "DESKTOP"}, false),
+				},
+			},
+			"transparent_page_sharing_scope": {
+				Description:  "Transparent page sharing scope for this Desktop Pool. Only applies when source is VIRTUAL_CENTER. VM: Inter-VM page sharing is not permitted. DESKTOP_POOL: Inter-VM page sharing among VMs belonging to the same Desktop pool is permitted. POD: Inter-VM page sharing among VMs belonging to the same Pod is permitted. GLOBAL: Inter-VM page sharing among all VMs on the same host is permitted.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "VM",
+				ValidateFunc: validation.StringInSlice([]string{"VM", "DESKTOP_POOL", "POD", "GLOBAL"}, false),
+			},
+			"delete_in_progress": {
+				Description: "Indicates whether the desktop pool is in the process of being deleted.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"machine_names": {
+				Description: "Names of the machines in the desktop pool.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"user_group_count": {
+				Description: "Count of user or group entitlements present for the desktop pool.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceDesktopPoolManualCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	poolType := "MANUAL"
+	source := d.Get("source").(string)
+	userAssignment := d.Get("user_assignment").(string)
+	agID := d.Get("access_group_id").(string)
+	enabled := d.Get("enabled").(bool)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	displayAssigned := d.Get("display_assigned_machine_name").(bool)
+	displayAlias := d.Get("display_machine_alias").(bool)
+
+	body := gohorizon.NewDesktopPoolCreateSpec(name, poolType)
+	body.Source = &source
+	body.UserAssignment = &userAssignment
+	body.AccessGroupId = &agID
+	body.Enabled = &enabled
+	body.EnableClientRestrictions = &clientRestrictions
+
+	if source == "VIRTUAL_CENTER" {
+		vCenterID := d.Get("vcenter_id").(string)
+		sessionType := d.Get("session_type").(string)
+		tpsScope := d.Get("transparent_page_sharing_scope").(string)
+		body.VcenterId = &vCenterID
+		body.SessionType = &sessionType
+		body.TransparentPageSharingScope = &tpsScope
+	}
+
+	if userAssignment == "DEDICATED" {
+		autoAssign := d.Get("automatic_user_assignment").(bool)
+		multiAssign := d.Get("allow_multiple_user_assignments").(bool)
+		body.AutomaticUserAssignment = &autoAssign
+		body.AllowMultipleUserAssignments = &multiAssign
+		body.DisplayAssignedMachineName = &displayAssigned
+		body.DisplayMachineAlias = &displayAlias
+	}
+
+	if dn, ok := d.GetOk("display_name"); ok {
+		displayName := dn.(string)
+		body.DisplayName = &displayName
+	}
+
+	if desc, ok := d.GetOk("description"); ok {
+		description := desc.(string)
+		body.Description = &description
+	}
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	if tags, ok := d.GetOk("cs_restriction_tags"); ok {
+		csTags := expandStringSet(tags.(*schema.Set))
+		body.CsRestrictionTags = &csTags
+	}
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	if dps, ok := d.GetOk("display_protocol_settings"); ok {
+		body.DisplayProtocolSettings = expandDesktopPoolDisplayProtocolSettingsCreateSpec(dps.([]interface{}))
+	}
+
+	candidates, diags := listDesktopPoolManualCandidates(ctx, &client, source, d.Get("vcenter_id").(string))
+	if diags != nil {
+		return diags
+	}
+
+	desired, diags := resourceDesktopPoolManualDesiredMachines(d, candidates)
+	if diags != nil {
+		return diags
+	}
+
+	machineIDs := []string{}
+	for _, machineID := range desired {
+		machineIDs = append(machineIDs, machineID)
+	}
+	body.Machines = &machineIDs
+
+	resp, err := client.InventoryApi.CreateDesktopPool(ctx).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	id, diags := findDesktopPoolIDByName(ctx, &client, name)
+	if diags != nil {
+		return diags
+	}
+
+	d.SetId(id)
+	return resourceDesktopPoolManualRead(ctx, d, meta)
+}
+
+func resourceDesktopPoolManualRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	poolInfo, resp, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the desktop pool was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if poolInfo.GetType() != "MANUAL" {
+		return diag.Errorf("desktop pool %s is a %s desktop pool, not a MANUAL desktop pool", id, poolInfo.GetType())
+	}
+
+	d.Set("access_group_id", poolInfo.AccessGroupId)
+	d.Set("allow_multiple_user_assignments", poolInfo.AllowMultipleUserAssignments)
+	d.Set("automatic_user_assignment", poolInfo.AutomaticUserAssignment)
+	d.Set("category_folder_name", poolInfo.CategoryFolderName)
+	d.Set("cs_restriction_tags", poolInfo.CsRestrictionTags)
+	d.Set("delete_in_progress", poolInfo.DeleteInProgress)
+	d.Set("description", poolInfo.Description)
+	d.Set("display_assigned_machine_name", poolInfo.DisplayAssignedMachineName)
+	d.Set("display_machine_alias", poolInfo.DisplayMachineAlias)
+	d.Set("display_name", poolInfo.DisplayName)
+	d.Set("enable_client_restrictions", poolInfo.EnableClientRestrictions)
+	d.Set("enabled", poolInfo.Enabled)
+	d.Set("name", poolInfo.Name)
+	d.Set("shortcut_locations_v2", poolInfo.ShortcutLocationsV2)
+	d.Set("source", poolInfo.Source)
+	d.Set("user_assignment", poolInfo.UserAssignment)
+	d.Set("user_group_count", poolInfo.UserGroupCount)
+	d.Set("vcenter_id", poolInfo.VcenterId)
+
+	if poolInfo.GetSource() == "VIRTUAL_CENTER" {
+		d.Set("session_type", poolInfo.SessionType)
+		d.Set("transparent_page_sharing_scope", poolInfo.TransparentPageSharingScope)
+	}
+
+	if poolInfo.DisplayProtocolSettings != nil {
+		if err := d.Set("display_protocol_settings", flattenDesktopPoolDisplayProtocolSettings(poolInfo.DisplayProtocolSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	machines, diags := listDesktopPoolMachines(ctx, &client, id)
+	if diags != nil {
+		return diags
+	}
+
+	machineNames := []string{}
+	for _, machine := range machines {
+		machineNames = append(machineNames, machine.GetName())
+	}
+	d.Set("machine_names", machineNames)
+
+	// track the machines using whichever attribute is configured,
+	// falling back to vm_names for imported vCenter pools
+	_, useIDs := d.GetOk("machine_ids")
+	if !useIDs && poolInfo.GetSource() == "VIRTUAL_CENTER" {
+		d.Set("vm_names", machineNames)
+		return nil
+	}
+
+	candidates, diags := listDesktopPoolManualCandidates(ctx, &client, poolInfo.GetSource(), poolInfo.GetVcenterId())
+	if diags != nil {
+		return diags
+	}
+
+	machineIDs := []string{}
+	for _, machineName := range machineNames {
+		if machineID, ok := candidates[machineName]; ok {
+			machineIDs = append(machineIDs, machineID)
+		}
+	}
+	d.Set("machine_ids", machineIDs)
+
+	return nil
+}
+
+func resourceDesktopPoolManualUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	source := d.Get("source").(string)
+	userAssignment := d.Get("user_assignment").(string)
+	displayAssigned := d.Get("display_assigned_machine_name").(bool)
+	displayAlias := d.Get("display_machine_alias").(bool)
+	displayName := d.Get("display_name").(string)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	enabled := d.Get("enabled").(bool)
+	agID := d.Get("access_group_id").(string)
+	description := d.Get("description").(string)
+
+	body := gohorizon.NewDesktopPoolUpdateSpec(false, false, displayAssigned, displayAlias, displayName, clientRestrictions, enabled)
+	body.AccessGroupId = &agID
+	body.Description = &description
+
+	if source == "VIRTUAL_CENTER" {
+		sessionType := d.Get("session_type").(string)
+		tpsScope := d.Get("transparent_page_sharing_scope").(string)
+		body.SessionType = &sessionType
+		body.TransparentPageSharingScope = &tpsScope
+	}
+
+	if userAssignment == "DEDICATED" {
+		autoAssign := d.Get("automatic_user_assignment").(bool)
+		multiAssign := d.Get("allow_multiple_user_assignments").(bool)
+		body.AutomaticUserAssignment = &autoAssign
+		body.AllowMultipleUserAssignments = &multiAssign
+	}
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	csTags := expandStringSet(d.Get("cs_restriction_tags").(*schema.Set))
+	body.CsRestrictionTags = &csTags
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	if dps, ok := d.GetOk("display_protocol_settings"); ok {
+		body.DisplayProtocolSettings = expandDesktopPoolDisplayProtocolSettingsUpdateSpec(dps.([]interface{}))
+	}
+
+	resp, err := client.InventoryApi.UpdateDesktopPool(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if d.HasChanges("machine_ids", "vm_names") {
+		if diags := resourceDesktopPoolManualUpdateMachines(ctx, d, &client); diags != nil {
+			return diags
+		}
+	}
+
+	return resourceDesktopPoolManualRead(ctx, d, meta)
+}
+
+// resourceDesktopPoolManualUpdateMachines adds and removes machines so the pool matches the configuration.
+func resourceDesktopPoolManualUpdateMachines(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient) diag.Diagnostics {
+	id := d.Id()
+
+	candidates, diags := listDesktopPoolManualCandidates(ctx, client, d.Get("source").(string), d.Get("vcenter_id").(string))
+	if diags != nil {
+		return diags
+	}
+
+	desired, diags := resourceDesktopPoolManualDesiredMachines(d, candidates)
+	if diags != nil {
+		return diags
+	}
+
+	machines, diags := listDesktopPoolMachines(ctx, client, id)
+	if diags != nil {
+		return diags
+	}
+
+	current := map[string]bool{}
+	removeList := []string{}
+	for _, machine := range machines {
+		current[machine.GetName()] = true
+		if _, ok := desired[machine.GetName()]; !ok {
+			removeList = append(removeList, machine.GetId())
+		}
+	}
+
+	addList := []string{}
+	for machineName, machineID := range desired {
+		if !current[machineName] {
+			addList = append(addList, machineID)
+		}
+	}
+
+	if len(removeList) > 0 {
+		results, resp, err := client.InventoryApi.RemoveMachines(ctx, id).Body(removeList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	if len(addList) > 0 {
+		results, resp, err := client.InventoryApi.AddMachines(ctx, id).Body(addList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	return nil
+}
+
+// resourceDesktopPoolManualSourceCustomizeDiff checks the settings that depend on the source of the pool.
+func resourceDesktopPoolManualSourceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	switch d.Get("source").(string) {
+	case "VIRTUAL_CENTER":
+		if !diffValueSet(d, "vcenter_id") {
+			return fmt.Errorf("vcenter_id must be set when source is \"VIRTUAL_CENTER\"")
+		}
+	case "UNMANAGED":
+		if d.NewValueKnown("vcenter_id") && d.Get("vcenter_id").(string) != "" {
+			return fmt.Errorf("vcenter_id must not be set when source is \"UNMANAGED\"")
+		}
+		if blockConfigured(d.GetRawConfig(), "vm_names") {
+			return fmt.Errorf("vm_names must not be set when source is \"UNMANAGED\", use machine_ids instead")
+		}
+	}
+
+	return nil
+}
+
+// listDesktopPoolManualCandidates returns a map of machine name to ID for the machines that
+// can be added to a manual desktop pool with the given source.
+func listDesktopPoolManualCandidates(ctx context.Context, client *gohorizon.APIClient, source, vCenterID string) (map[string]string, diag.Diagnostics) {
+	candidates := map[string]string{}
+
+	switch source {
+	case "VIRTUAL_CENTER":
+		vms, resp, err := client.ExternalApi.ListVirtualMachines(ctx).VcenterId(vCenterID).Execute()
+		if err != nil {
+			return nil, returnResponseErr(resp, err)
+		}
+		for _, vm := range vms {
+			candidates[vm.GetName()] = vm.GetId()
+		}
+	case "UNMANAGED":
+		for page := int32(1); ; page++ {
+			physicalMachines, resp, err := client.InventoryApi.ListPhysicalMachines(ctx).Page(page).Size(listPageSize).Execute()
+			if err != nil {
+				return nil, returnResponseErr(resp, err)
+			}
+			for _, machine := range physicalMachines {
+				candidates[machine.GetName()] = machine.GetId()
+			}
+			if len(physicalMachines) < listPageSize {
+				break
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// resourceDesktopPoolManualDesiredMachines resolves the configured machines to a map of machine name to ID.
+func resourceDesktopPoolManualDesiredMachines(d *schema.ResourceData, candidates map[string]string) (map[string]string, diag.Diagnostics) {
+	desired := map[string]string{}
+	var diags diag.Diagnostics
+
+	if vmNames, ok := d.GetOk("vm_names"); ok {
+		for _, vmName := range expandStringSet(vmNames.(*schema.Set)) {
+			machineID, found := candidates[vmName]
+			if !found {
+				diags = append(diags, diag.Errorf("could not find any virtual machine with name \"%s\"", vmName)...)
+				continue
+			}
+			desired[vmName] = machineID
+		}
+		return desired, diags
+	}
+
+	candidateNames := map[string]string{}
+	for machineName, machineID := range candidates {
+		candidateNames[machineID] = machineName
+	}
+
+	for _, machineID := range expandStringSet(d.Get("machine_ids").(*schema.Set)) {
+		machineName, found := candidateNames[machineID]
+		if !found {
+			diags = append(diags, diag.Errorf("could not find any machine with ID \"%s\"", machineID)...)
+			continue
+		}
+		desired[machineName] = machineID
+	}
+
+	return desired, diags
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceDesktopPoolManualPlan(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      string
+		expectError string
+	}{
+		{
+			name: "valid unmanaged pool",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name            = "physical-pool"
+  access_group_id = "access-group"
+  source          = "UNMANAGED"
+  user_assignment = "DEDICATED"
+  machine_ids     = ["machine-1", "machine-2"]
+}
+`,
+		},
+		{
+			name: "valid vCenter pool",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name            = "vm_pool"
+  access_group_id = "access-group"
+  source          = "VIRTUAL_CENTER"
+  user_assignment = "FLOATING"
+  vcenter_id      = "vcenter"
+  vm_names        = ["vdi-1", "vdi-2"]
+}
+`,
+		},
+		{
+			name: "invalid name",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name            = "vm.pool"
+  access_group_id = "access-group"
+  source          = "UNMANAGED"
+  user_assignment = "FLOATING"
+  machine_ids     = ["machine-1"]
+}
+`,
+			expectError: `name must contain only alphanumerics`,
+		},
+		{
+			name: "vCenter pool without vCenter",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name            = "vm-pool"
+  access_group_id = "access-group"
+  source          = "VIRTUAL_CENTER"
+  user_assignment = "FLOATING"
+  vm_names        = ["vdi-1"]
+}
+`,
+			expectError: `vcenter_id must be set`,
+		},
+		{
+			name: "unmanaged pool with vCenter",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name            = "physical-pool"
+  access_group_id = "access-group"
+  source          = "UNMANAGED"
+  user_assignment = "FLOATING"
+  vcenter_id      = "vcenter"
+  machine_ids     = ["machine-1"]
+}
+`,
+			expectError: `vcenter_id must not be set`,
+		},
+		{
+			name: "unmanaged pool with VM names",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name            = "physical-pool"
+  access_group_id = "access-group"
+  source          = "UNMANAGED"
+  user_assignment = "FLOATING"
+  vm_names        = ["vdi-1"]
+}
+`,
+			expectError: `vm_names must not be set`,
+		},
+		{
+			name: "machine IDs and VM names",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name            = "vm-pool"
+  access_group_id = "access-group"
+  source          = "VIRTUAL_CENTER"
+  user_assignment = "FLOATING"
+  vcenter_id      = "vcenter"
+  machine_ids     = ["vm-1"]
+  vm_names        = ["vdi-1"]
+}
+`,
+			expectError: `only one of`,
+		},
+		{
+			name: "automatic and multiple assignment",
+			config: `
+resource "horizon_desktop_pool_manual" "test" {
+  name                            = "physical-pool"
+  access_group_id                 = "access-group"
+  source                          = "UNMANAGED"
+  user_assignment                 = "DEDICATED"
+  automatic_user_assignment       = true
+  allow_multiple_user_assignments = true
+  machine_ids                     = ["machine-1"]
+}
+`,
+			expectError: `cannot both be true`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHorizonServer(t, nil)
+			testPlan(t, testProviderConfig(srv)+tc.config, tc.expectError)
+		})
+	}
+}
+
+func TestResourceDesktopPoolManualRead(t *testing.T) {
+	machines := []map[string]string{{"id": "machine-1", "name": "vdi-1"}, {"id": "machine-2", "name": "vdi-2"}}
+
+	testResourceRead(t, resourceDesktopPoolManual(), "pool", "/rest/inventory/v5/desktop-pools/pool", map[string]http.HandlerFunc{
+		"/rest/inventory/v1/machines": func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.URL.Query().Get("filter"), `"value":"pool"`) {
+				t.Errorf("machines filter = %q, want the desktop pool", r.URL.Query().Get("filter"))
+			}
+			writeTestJSON(w, http.StatusOK, machines)
+		},
+		"/rest/inventory/v1/physical-machines": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, machines)
+		},
+	}, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			raw:    map[string]interface{}{"machine_ids": []interface{}{"machine-1"}},
+			wantID: "",
+		},
+		{
+			name:      "automated desktop pool",
+			object:    map[string]interface{}{"id": "pool", "type": "AUTOMATED", "source": "INSTANT_CLONE"},
+			wantID:    "pool",
+			wantError: "not a MANUAL desktop pool",
+		},
+		{
+			name:   "vCenter pool tracked by name",
+			object: map[string]interface{}{"id": "pool", "type": "MANUAL", "source": "VIRTUAL_CENTER", "vcenter_id": "vcenter"},
+			raw:    map[string]interface{}{"vm_names": []interface{}{"vdi-1"}},
+			wantID: "pool",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got, want := sortedStringSet(d.Get("vm_names").(*schema.Set)), []string{"vdi-1", "vdi-2"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("vm_names = %v, want %v", got, want)
+				}
+			},
+		},
+		{
+			name:   "unmanaged pool tracked by ID",
+			object: map[string]interface{}{"id": "pool", "type": "MANUAL", "source": "UNMANAGED"},
+			raw:    map[string]interface{}{"machine_ids": []interface{}{"machine-1"}},
+			wantID: "pool",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got, want := sortedStringSet(d.Get("machine_ids").(*schema.Set)), []string{"machine-1", "machine-2"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("machine_ids = %v, want %v", got, want)
+				}
+			},
+		},
+	})
+}
+
+func TestResourceDesktopPoolManualDesiredMachines(t *testing.T) {
+	candidates := map[string]string{"vdi-1": "machine-1", "vdi-2": "machine-2"}
+
+	cases := []struct {
+		name       string
+		raw        map[string]interface{}
+		want       map[string]string
+		wantErrors int
+	}{
+		{
+			name: "VM names",
+			raw:  map[string]interface{}{"vm_names": []interface{}{"vdi-1", "vdi-2"}},
+			want: map[string]string{"vdi-1": "machine-1", "vdi-2": "machine-2"},
+		},
+		{
+			name:       "unknown VM names",
+			raw:        map[string]interface{}{"vm_names": []interface{}{"vdi-1", "vdi-3", "vdi-4"}},
+			want:       map[string]string{"vdi-1": "machine-1"},
+			wantErrors: 2,
+		},
+		{
+			name: "machine IDs",
+			raw:  map[string]interface{}{"machine_ids": []interface{}{"machine-2"}},
+			want: map[string]string{"vdi-2": "machine-2"},
+		},
+		{
+			name:       "unknown machine ID",
+			raw:        map[string]interface{}{"machine_ids": []interface{}{"machine-2", "machine-3"}},
+			want:       map[string]string{"vdi-2": "machine-2"},
+			wantErrors: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceDesktopPoolManual().Schema, tc.raw)
+
+			got, diags := resourceDesktopPoolManualDesiredMachines(d, candidates)
+			if len(diags) != tc.wantErrors {
+				t.Errorf("got %d diagnostics, want %d: %#v", len(diags), tc.wantErrors, diags)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("desired machines = %v, want %v", got, tc.want)
+			}
+			for name, id := range tc.want {
+				if got[name] != id {
+					t.Errorf("desired machines = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func sortedStringSet(s *schema.Set) []string {
+	values := expandStringSet(s)
+	sort.Strings(values)
+
+	return values
+}
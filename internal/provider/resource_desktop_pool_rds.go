@@ -0,0 +1,296 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceDesktopPoolRDS() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage Horizon RDS Desktop Pools. RDS desktop pools publish session-based desktops from an RDS farm. The access group, session settings and display protocols of an RDS desktop pool are inherited from its farm.",
+
+		CreateContext: resourceDesktopPoolRDSCreate,
+		ReadContext:   resourceDesktopPoolRDSRead,
+		UpdateContext: resourceDesktopPoolRDSUpdate,
+		DeleteContext: resourceDesktopPoolDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"farm_id": {
+				Description: "ID of the farm that backs the desktop pool. The farm must not already be associated with another RDS desktop pool.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "Name of the Desktop Pool. This property must contain only alphanumerics, underscores, and dashes.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"allow_multiple_sessions_per_user": {
+				Description: "Indicates whether multiple sessions are allowed per user for this pool.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"category_folder_name": {
+				Description: "Name of the category folder in the user's OS containing a shortcut to the desktop pool. Will be unset if the desktop does not belong to a category.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"cloud_brokered": {
+				Description: "Indicates whether the RDS desktop pool is brokered by Horizon Cloud Services.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"cs_restriction_tags": {
+				Description: "List of Connection server restriction tags to which the access to the desktop pool is restricted. If this property is not set it indicates that desktop pool can be accessed from any connection server.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"description": {
+				Description: "Description of the desktop pool.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"display_name": {
+				Description: "Display name of the desktop pool. If the display name is left blank, it defaults to name.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"enable_client_restrictions": {
+				Description: "Client restrictions to be applied to the desktop pool.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"enabled": {
+				Description: "Indicates whether the desktop pool is enabled for brokering.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"shortcut_locations_v2": {
+				Description: "Locations of the category folder in the user's OS containing a shortcut to the desktop pool. This is required if the category_folder_name is set.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"START_MENU", "DESKTOP"}, false),
+				},
+			},
+			"access_group_id": {
+				Description: "Access group of the desktop pool, inherited from the farm.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"delete_in_progress": {
+				Description: "Indicates whether the desktop pool is in the process of being deleted.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"display_protocol_settings": {
+				Description: "Display protocol settings of the desktop pool, inherited from the farm.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_users_to_choose_protocol": {
+							Description: "Indicates whether the users can choose the protocol.",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"default_display_protocol": {
+							Description: "The default display protocol for the desktop pool.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"display_protocols": {
+							Description: "The display protocols supported by the desktop pool.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"html_access_enabled": {
+							Description: "Indicates whether HTML Access is enabled for the desktop pool.",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"session_collaboration_enabled": {
+							Description: "Indicates whether session collaboration is enabled for the desktop pool.",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"user_group_count": {
+				Description: "Count of user or group entitlements present for the desktop pool.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceDesktopPoolRDSCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	poolType := "RDS"
+	farmID := d.Get("farm_id").(string)
+	enabled := d.Get("enabled").(bool)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	multiSession := d.Get("allow_multiple_sessions_per_user").(bool)
+	cloudBrokered := d.Get("cloud_brokered").(bool)
+
+	body := gohorizon.NewDesktopPoolCreateSpec(name, poolType)
+	body.FarmId = &farmID
+	body.Enabled = &enabled
+	body.EnableClientRestrictions = &clientRestrictions
+	body.AllowRdsPoolMultiSessionPerUser = &multiSession
+	body.CloudBrokered = &cloudBrokered
+
+	if dn, ok := d.GetOk("display_name"); ok {
+		displayName := dn.(string)
+		body.DisplayName = &displayName
+	}
+
+	if desc, ok := d.GetOk("description"); ok {
+		description := desc.(string)
+		body.Description = &description
+	}
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	if tags, ok := d.GetOk("cs_restriction_tags"); ok {
+		csTags := expandStringSet(tags.(*schema.Set))
+		body.CsRestrictionTags = &csTags
+	}
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	resp, err := client.InventoryApi.CreateDesktopPool(ctx).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	id, diags := findDesktopPoolIDByName(ctx, &client, name)
+	if diags != nil {
+		return diags
+	}
+
+	d.SetId(id)
+	return resourceDesktopPoolRDSRead(ctx, d, meta)
+}
+
+func resourceDesktopPoolRDSRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	poolInfo, resp, err := client.InventoryApi.GetDesktopPoolV5(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the desktop pool was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if poolInfo.GetType() != "RDS" {
+		return diag.Errorf("desktop pool %s is a %s desktop pool, not an RDS desktop pool", id, poolInfo.GetType())
+	}
+
+	d.Set("access_group_id", poolInfo.AccessGroupId)
+	d.Set("allow_multiple_sessions_per_user", poolInfo.AllowRdsPoolMultiSessionPerUser)
+	d.Set("category_folder_name", poolInfo.CategoryFolderName)
+	d.Set("cloud_brokered", poolInfo.CloudBrokered)
+	d.Set("cs_restriction_tags", poolInfo.CsRestrictionTags)
+	d.Set("delete_in_progress", poolInfo.DeleteInProgress)
+	d.Set("description", poolInfo.Description)
+	d.Set("display_name", poolInfo.DisplayName)
+	d.Set("enable_client_restrictions", poolInfo.EnableClientRestrictions)
+	d.Set("enabled", poolInfo.Enabled)
+	d.Set("farm_id", poolInfo.FarmId)
+	d.Set("name", poolInfo.Name)
+	d.Set("shortcut_locations_v2", poolInfo.ShortcutLocationsV2)
+	d.Set("user_group_count", poolInfo.UserGroupCount)
+
+	if poolInfo.DisplayProtocolSettings != nil {
+		dpSettings := poolInfo.DisplayProtocolSettings
+		displayProtocol := map[string]interface{}{
+			"allow_users_to_choose_protocol": dpSettings.GetAllowUsersToChooseProtocol(),
+			"default_display_protocol":       dpSettings.GetDefaultDisplayProtocol(),
+			"display_protocols":              dpSettings.GetDisplayProtocols(),
+			"html_access_enabled":            dpSettings.GetHtmlAccessEnabled(),
+			"session_collaboration_enabled":  dpSettings.GetSessionCollaborationEnabled(),
+		}
+		if err := d.Set("display_protocol_settings", []interface{}{displayProtocol}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDesktopPoolRDSUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	displayName := d.Get("display_name").(string)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	enabled := d.Get("enabled").(bool)
+	multiSession := d.Get("allow_multiple_sessions_per_user").(bool)
+	cloudBrokered := d.Get("cloud_brokered").(bool)
+	description := d.Get("description").(string)
+
+	body := gohorizon.NewDesktopPoolUpdateSpec(false, false, false, false, displayName, clientRestrictions, enabled)
+	body.AllowRdsPoolMultiSessionPerUser = &multiSession
+	body.CloudBrokered = &cloudBrokered
+	body.Description = &description
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	csTags := expandStringSet(d.Get("cs_restriction_tags").(*schema.Set))
+	body.CsRestrictionTags = &csTags
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	resp, err := client.InventoryApi.UpdateDesktopPool(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	return resourceDesktopPoolRDSRead(ctx, d, meta)
+}
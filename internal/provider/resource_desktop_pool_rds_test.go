@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceDesktopPoolRDSCreate(t *testing.T) {
+	pool := map[string]interface{}{
+		"id":      "pool",
+		"name":    "rds-pool",
+		"type":    "RDS",
+		"farm_id": "farm",
+		"enabled": true,
+		"display_protocol_settings": map[string]interface{}{
+			"allow_users_to_choose_protocol": true,
+			"default_display_protocol":       "BLAST",
+			"display_protocols":              []string{"BLAST", "PCOIP"},
+		},
+	}
+
+	var created map[string]interface{}
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/inventory/v1/desktop-pools": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("method = %s, want %s", r.Method, http.MethodPost)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("error decoding the create spec: %s", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		},
+		"/rest/inventory/v5/desktop-pools": func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.URL.Query().Get("filter"), `"value":"rds-pool"`) {
+				t.Errorf("desktop pools filter = %q, want the pool name", r.URL.Query().Get("filter"))
+			}
+			writeTestJSON(w, http.StatusOK, []interface{}{pool})
+		},
+		"/rest/inventory/v5/desktop-pools/pool": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, pool)
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceDesktopPoolRDS().Schema, map[string]interface{}{
+		"name":                  "rds-pool",
+		"farm_id":               "farm",
+		"shortcut_locations_v2": []interface{}{"START_MENU"},
+	})
+
+	if diags := resourceDesktopPoolRDSCreate(context.Background(), d, testAPIClient(srv)); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+
+	if created["type"] != "RDS" || created["farm_id"] != "farm" {
+		t.Errorf("create spec = %v, want an RDS desktop pool on farm", created)
+	}
+	if _, ok := created["category_folder_name"]; ok {
+		t.Errorf("create spec sent category_folder_name although it is not set")
+	}
+	if d.Id() != "pool" {
+		t.Errorf("ID = %q, want %q", d.Id(), "pool")
+	}
+	if got := d.Get("display_protocol_settings.0.default_display_protocol"); got != "BLAST" {
+		t.Errorf("default_display_protocol = %v, want BLAST", got)
+	}
+}
+
+func TestResourceDesktopPoolRDSRead(t *testing.T) {
+	testResourceRead(t, resourceDesktopPoolRDS(), "pool", "/rest/inventory/v5/desktop-pools/pool", nil, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:      "manual desktop pool",
+			object:    map[string]interface{}{"id": "pool", "type": "MANUAL"},
+			wantID:    "pool",
+			wantError: "not an RDS desktop pool",
+		},
+		{
+			name:   "RDS desktop pool",
+			object: map[string]interface{}{"id": "pool", "type": "RDS", "farm_id": "farm"},
+			wantID: "pool",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := d.Get("farm_id"); got != "farm" {
+					t.Errorf("farm_id = %v, want %q", got, "farm")
+				}
+			},
+		},
+	})
+}
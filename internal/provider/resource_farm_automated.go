@@ -0,0 +1,1019 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceFarmAutomated() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage Horizon Automated (instant clone) RDS Farms.",
+
+		CreateContext: resourceFarmAutomatedCreate,
+		ReadContext:   resourceFarmAutomatedRead,
+		UpdateContext: resourceFarmAutomatedUpdate,
+		DeleteContext: resourceFarmDelete,
+
+		CustomizeDiff: resourceFarmAutomatedCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"access_group_id": {
+				Description: "Access groups can organize the entities such as farms in the organization. They can also be used for delegated administration.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Name of the farm. This property must contain only alphanumerics, underscores, and dashes.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"vcenter_id": {
+				Description: "ID of the virtual center server.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"clone_prep_settings": {
+				Description: "ClonePrep is a VMware system tool executed by Instant Clone Engine during a instant clone machine deployment. ClonePrep personalizes each RDS Server created from the Master image.",
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ad_container_rdn": {
+							Description: "Instant Clone Engine Active Directory container for ClonePrep.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"instant_clone_domain_account_id": {
+							Description: "This is the administrator which will add the RDS Servers to its domain upon creation.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"priming_computer_account": {
+							Description: "Instant Clone publishing needs an additional computer account in the same AD domain as the clones. This field accepts the pre-created computer accounts.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"post_synchronization_script_name": {
+							Description: "Post synchronization script. ClonePrep can run a customization script on instant-clone RDS Servers after they are created or recovered or a new image is pushed. Provide the path to the script on the parent virtual machine.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"post_synchronization_script_parameters": {
+							Description: "Post synchronization script parameters.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"power_off_script_name": {
+							Description: "Power off script. ClonePrep can run a customization script on instant-clone RDS Servers before they are powered off. Provide the path to the script on the parent virtual machine.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"power_off_script_parameters": {
+							Description: "Power off script parameters.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"reuse_pre_existing_accounts": {
+							Description: "Indicates whether to allow the use of existing AD computer accounts when the VM names of newly created clones match the existing computer account names.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
+			},
+			"description": {
+				Description: "Description of the farm.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"display_name": {
+				Description: "Display name of the farm. If the display name is left blank, it defaults to name.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"display_protocol_settings": farmDisplayProtocolSettingsSchema(),
+			"enable_provisioning": {
+				Description: "Indicates whether provisioning is enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"enabled": {
+				Description: "Indicates whether the farm is enabled for brokering.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"load_balancer_settings": farmLoadBalancerSettingsSchema(),
+			"max_session_type": {
+				Description:  "RDS Server type for max sessions. UNLIMITED: The RDS Server has an unlimited number of sessions. LIMITED: The RDS Server has a limited number of sessions.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "UNLIMITED",
+				ValidateFunc: validation.StringInSlice([]string{"UNLIMITED", "LIMITED"}, false),
+			},
+			"max_sessions": {
+				Description:  "Maximum number of sessions allowed for each RDS Server. This is required if max_session_type is set to LIMITED.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"min_ready_vms": {
+				Description: "Minimum number of ready (provisioned) RDS Servers during Instant clone maintenance operations.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+			},
+			"pattern_naming_settings": {
+				Description: "Naming pattern settings for the RDS Servers of the farm.",
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"naming_pattern": {
+							Description: "RDS Servers will be named according to the specified naming pattern. By default, view manager appends a unique number to the specified pattern to provide a unique name for each RDS Server. To place this unique number elsewhere in the pattern, use '{n}'. (For example: rds-{n}-sales.) Machine names are constrained to a maximum size of 15 characters including the unique number.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"max_number_of_rds_servers": {
+							Description: "Maximum number of RDS Servers in the farm.",
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     1,
+						},
+					},
+				},
+			},
+			"provisioning_settings": {
+				Description: "Virtual center provisioning settings for the farm.",
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"base_snapshot_id": {
+							Description: "Base image snapshot to use for the RDS Servers.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"datacenter_id": {
+							Description: "Datacenter within which the farm is configured.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"host_or_cluster_id": {
+							Description: "Host or cluster where the RDS Servers are deployed in.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"parent_vm_id": {
+							Description: "Parent virtual machine to use for the RDS Servers.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+						"resource_pool_id": {
+							Description: "Resource pool to deploy the RDS Servers.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"vm_folder_id": {
+							Description: "VM folder where the RDS Servers are deployed to.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+			"server_error_threshold": {
+				Description: "The minimum number of RDS Servers that must be fully operational in order to avoid showing the farm in an error state.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+			},
+			"session_settings": farmSessionSettingsSchema(),
+			"stop_provisioning_on_error": {
+				Description: "Indicates whether provisioning on all VMs stops on error.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"storage_settings": {
+				Description: "Virtual center storage settings for the farm.",
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"datastores": {
+							Description: "IDs of the datastores used to store the RDS Servers.",
+							Type:        schema.TypeSet,
+							Required:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"replica_disk_datastore_id": {
+							Description: "Datastore to store replica disks for instant clone RDS Servers. This property is required if use_separate_datastores_replica_and_os_disks is set to true.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"use_separate_datastores_replica_and_os_disks": {
+							Description: "Indicates whether to use separate datastores for replica and OS disks.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							ForceNew:    true,
+						},
+						"use_view_storage_accelerator": {
+							Description: "Indicates whether to use View Storage Accelerator.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							ForceNew:    true,
+						},
+						"use_vsan": {
+							Description: "Indicates whether to use vSphere vSAN.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+			"transparent_page_sharing_scope": {
+				Description:  "Transparent page sharing scope for the farm. VM: Inter-VM page sharing is not permitted. FARM: Inter-VM page sharing among VMs belonging to the same automated farm is permitted. POD: Inter-VM page sharing among VMs belonging to the same Pod is permitted. GLOBAL: Inter-VM page sharing among all VMs on the same host is permitted.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "VM",
+				ValidateFunc: validation.StringInSlice([]string{"VM", "FARM", "POD", "GLOBAL"}, false),
+			},
+			"use_custom_script_for_load_balancing": {
+				Description: "Indicates whether to use custom scripts for load balancing.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"delete_in_progress": {
+				Description: "Indicates whether the farm is in the process of being deleted.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"desktop_pool_id": {
+				Description: "ID of the RDS desktop pool associated with the farm.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"image_source": {
+				Description: "Source of image used in the farm. Possible values are VIRTUAL_CENTER: Image was created in virtual center. IMAGE_CATALOG: Image was created in image catalog.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// farmDisplayProtocolSettingsSchema is shared by the farm resources.
+func farmDisplayProtocolSettingsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Display protocol settings for the farm.",
+		Type:        schema.TypeList,
+		Optional:    true,
+		Computed:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"allow_users_to_choose_protocol": {
+					Description: "Indicates whether the users can choose the protocol.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+				},
+				"default_display_protocol": {
+					Description:  "The default server display protocol, when users are not allowed to choose the protocol.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "PCOIP",
+					ValidateFunc: validation.StringInSlice([]string{"PCOIP", "RDP", "BLAST"}, false),
+				},
+				"grid_vgpus_enabled": {
+					Description: "Indicates whether the RDS Servers of an instant clone farm support NVIDIA GRID vGPUs. This can only be set when the farm is created.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					ForceNew:    true,
+				},
+				"session_collaboration_enabled": {
+					Description: "Indicates whether session collaboration feature is enabled. Session collaboration allows a user to share their remote session with other users.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+				},
+			},
+		},
+	}
+}
+
+// farmLoadBalancerSettingsSchema is shared by the farm resources.
+func farmLoadBalancerSettingsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "RDSH load balancer settings for the farm. A threshold of 0 means the metric is not considered for load balancing.",
+		Type:        schema.TypeList,
+		Optional:    true,
+		Computed:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cpu_threshold": {
+					Description:  "Threshold of CPU usage, in percentage.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntBetween(0, 100),
+				},
+				"disk_queue_length_threshold": {
+					Description:  "Threshold of the average number of both read and write requests that were queued for the selected disk during the sample interval.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"disk_read_latency_threshold": {
+					Description:  "Threshold of the average time, in milliseconds, of a read of data from the disk.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"disk_write_latency_threshold": {
+					Description:  "Threshold of the average time, in milliseconds, of a write of data to the disk.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"include_session_count": {
+					Description: "Indicates whether to include session count for load balancing.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     true,
+				},
+				"memory_threshold": {
+					Description:  "Threshold of memory usage, in percentage.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntBetween(0, 100),
+				},
+			},
+		},
+	}
+}
+
+// farmSessionSettingsSchema is shared by the farm resources.
+func farmSessionSettingsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Session timeout settings for the farm.",
+		Type:        schema.TypeList,
+		Optional:    true,
+		Computed:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"disconnected_session_timeout_minutes": {
+					Description:  "Disconnected sessions timeout (in minutes). This is required if disconnected_session_timeout_policy is set to AFTER.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"disconnected_session_timeout_policy": {
+					Description:  "Log-off policy after disconnected session. IMMEDIATELY: Immediately Logoff after user disconnect. AFTER: Logoff after the specified number of minutes after user disconnect. NEVER: Do not logoff after user disconnect.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "NEVER",
+					ValidateFunc: validation.StringInSlice([]string{"IMMEDIATELY", "AFTER", "NEVER"}, false),
+				},
+				"empty_session_timeout_minutes": {
+					Description:  "Application empty session timeout (in minutes). An empty session (that has no remote-able window) is disconnected after the timeout. This is required if empty_session_timeout_policy is set to AFTER.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      1,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"empty_session_timeout_policy": {
+					Description:  "Application empty session timeout policy. IMMEDIATE: Empty session will be disconnected immediately. NEVER: Empty session will never disconnected. AFTER: Empty session will be disconnected after specified number of minutes.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "AFTER",
+					ValidateFunc: validation.StringInSlice([]string{"IMMEDIATE", "AFTER", "NEVER"}, false),
+				},
+				"logoff_after_timeout": {
+					Description: "Indicates whether the empty application sessions are logged off (true) or disconnected (false) after timeout.",
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+				},
+				"pre_launch_session_timeout_minutes": {
+					Description:  "Application pre-launch session timeout (in minutes). A pre-launch session is disconnected after the timeout. This is required if pre_launch_session_timeout_policy is set to AFTER.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      10,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"pre_launch_session_timeout_policy": {
+					Description:  "Application pre-launch session timeout policy. AFTER: Pre-launched session is disconnected after specified number of minutes. NEVER: Pre-launched session is never disconnected.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "AFTER",
+					ValidateFunc: validation.StringInSlice([]string{"AFTER", "NEVER"}, false),
+				},
+			},
+		},
+	}
+}
+
+func resourceFarmAutomatedCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// a max_sessions that is not known until apply is treated as set
+	_, maxSessionsSet := d.GetOk("max_sessions")
+	maxSessionsSet = maxSessionsSet || !d.NewValueKnown("max_sessions")
+
+	if d.Get("max_session_type").(string) == "LIMITED" {
+		if !maxSessionsSet {
+			return fmt.Errorf("max_sessions must be set when max_session_type is \"LIMITED\"")
+		}
+	} else if maxSessionsSet {
+		return fmt.Errorf("max_sessions can only be set when max_session_type is \"LIMITED\"")
+	}
+
+	separateDatastores := d.Get("storage_settings.0.use_separate_datastores_replica_and_os_disks").(bool)
+	if separateDatastores && !diffValueSet(d, "storage_settings.0.replica_disk_datastore_id") {
+		return fmt.Errorf("use_separate_datastores_replica_and_os_disks cannot be set if replica_disk_datastore_id is not specified")
+	}
+
+	return nil
+}
+
+func resourceFarmAutomatedCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	farmType := "AUTOMATED"
+	agID := d.Get("access_group_id").(string)
+	vCenterID := d.Get("vcenter_id").(string)
+	enabled := d.Get("enabled").(bool)
+	enableProvisioning := d.Get("enable_provisioning").(bool)
+	stopOnErr := d.Get("stop_provisioning_on_error").(bool)
+	maxSessionType := d.Get("max_session_type").(string)
+	minReady := int32(d.Get("min_ready_vms").(int))
+	errThreshold := int32(d.Get("server_error_threshold").(int))
+	tpsScope := d.Get("transparent_page_sharing_scope").(string)
+	customScript := d.Get("use_custom_script_for_load_balancing").(bool)
+
+	clonePrep := d.Get("clone_prep_settings").([]interface{})[0].(map[string]interface{})
+	icdaID := clonePrep["instant_clone_domain_account_id"].(string)
+	adRDN := clonePrep["ad_container_rdn"].(string)
+	reuse := clonePrep["reuse_pre_existing_accounts"].(bool)
+	pca := clonePrep["priming_computer_account"].(string)
+	psScriptName := clonePrep["post_synchronization_script_name"].(string)
+	psScriptParams := clonePrep["post_synchronization_script_parameters"].(string)
+	poScriptName := clonePrep["power_off_script_name"].(string)
+	poScriptParams := clonePrep["power_off_script_parameters"].(string)
+
+	clonePrepSettings := gohorizon.NewFarmCloneprepCustomizationSettingsCreateSpec()
+	clonePrepSettings.PostSynchronizationScriptName = &psScriptName
+	clonePrepSettings.PostSynchronizationScriptParameters = &psScriptParams
+	clonePrepSettings.PowerOffScriptName = &poScriptName
+	clonePrepSettings.PowerOffScriptParameters = &poScriptParams
+	clonePrepSettings.PrimingComputerAccount = &pca
+
+	custSettings := gohorizon.NewFarmCustomizationSettingsCreateSpec(icdaID)
+	custSettings.AdContainerRdn = &adRDN
+	custSettings.ReusePreExistingAccounts = &reuse
+	custSettings.CloneprepCustomizationSettings = clonePrepSettings
+
+	patternNamingRaw := d.Get("pattern_naming_settings").([]interface{})[0].(map[string]interface{})
+	namingPattern := patternNamingRaw["naming_pattern"].(string)
+	maxServers := int32(patternNamingRaw["max_number_of_rds_servers"].(int))
+	patternNaming := gohorizon.NewFarmRDSServersPatternNamingSettingsCreateSpec(namingPattern)
+	patternNaming.MaxNumberOfRdsServers = &maxServers
+
+	provSettingsRaw := d.Get("provisioning_settings").([]interface{})[0].(map[string]interface{})
+	dcID := provSettingsRaw["datacenter_id"].(string)
+	hcID := provSettingsRaw["host_or_cluster_id"].(string)
+	rpID := provSettingsRaw["resource_pool_id"].(string)
+	folderID := provSettingsRaw["vm_folder_id"].(string)
+	parentVMID := provSettingsRaw["parent_vm_id"].(string)
+	baseSnapID := provSettingsRaw["base_snapshot_id"].(string)
+	provSettings := gohorizon.NewFarmProvisioningSettingsCreateSpec(dcID, hcID, rpID, folderID)
+	provSettings.ParentVmId = &parentVMID
+	provSettings.BaseSnapshotId = &baseSnapID
+
+	storSetRaw := d.Get("storage_settings").([]interface{})[0].(map[string]interface{})
+	datastores := []gohorizon.FarmDatastoreSettingsCreateSpec{}
+	for _, dsID := range expandStringSet(storSetRaw["datastores"].(*schema.Set)) {
+		datastores = append(datastores, *gohorizon.NewFarmDatastoreSettingsCreateSpec(dsID))
+	}
+	rddID := storSetRaw["replica_disk_datastore_id"].(string)
+	separateds := storSetRaw["use_separate_datastores_replica_and_os_disks"].(bool)
+	useVSA := storSetRaw["use_view_storage_accelerator"].(bool)
+	vSAN := storSetRaw["use_vsan"].(bool)
+
+	storageSettings := gohorizon.NewFarmStorageSettingsCreateSpec(datastores)
+	storageSettings.UseViewStorageAccelerator = &useVSA
+	storageSettings.UseVsan = &vSAN
+
+	if rddID != "" {
+		storageSettings.ReplicaDiskDatastoreId = &rddID
+		storageSettings.UseSeparateDatastoresReplicaAndOsDisks = &separateds
+	}
+
+	autoSettings := gohorizon.NewFarmAutomatedSettingsCreateSpec(*custSettings, maxSessionType, *patternNaming, *provSettings, *storageSettings, vCenterID)
+	autoSettings.EnableProvisioning = &enableProvisioning
+	autoSettings.StopProvisioningOnError = &stopOnErr
+	autoSettings.MinReadyVms = &minReady
+	autoSettings.TransparentPageSharingScope = &tpsScope
+
+	if maxSessionType == "LIMITED" {
+		maxSessions := int32(d.Get("max_sessions").(int))
+		autoSettings.MaxSessions = &maxSessions
+	}
+
+	body := gohorizon.NewFarmCreateSpec(agID, name, farmType)
+	body.AutomatedFarmSettings = autoSettings
+	body.Enabled = &enabled
+	body.ServerErrorThreshold = &errThreshold
+	body.UseCustomScriptForLoadBalancing = &customScript
+
+	if dn, ok := d.GetOk("display_name"); ok {
+		displayName := dn.(string)
+		body.DisplayName = &displayName
+	}
+
+	if desc, ok := d.GetOk("description"); ok {
+		description := desc.(string)
+		body.Description = &description
+	}
+
+	if dps, ok := d.GetOk("display_protocol_settings"); ok {
+		body.DisplayProtocolSettings = expandFarmDisplayProtocolSettingsCreateSpec(dps.([]interface{}))
+	}
+
+	if lbs, ok := d.GetOk("load_balancer_settings"); ok {
+		body.LoadBalancerSettings = expandFarmLoadBalancerSettingsCreateSpec(lbs.([]interface{}))
+	}
+
+	if ss, ok := d.GetOk("session_settings"); ok {
+		body.SessionSettings = expandFarmSessionSettingsCreateSpec(ss.([]interface{}))
+	}
+
+	resp, err := client.InventoryApi.CreateFarm(ctx).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	id, diags := findFarmIDByName(ctx, &client, name)
+	if diags != nil {
+		return diags
+	}
+
+	d.SetId(id)
+	return resourceFarmAutomatedRead(ctx, d, meta)
+}
+
+func resourceFarmAutomatedRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	farmInfo, resp, err := client.InventoryApi.GetFarmV3(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the farm was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if farmInfo.GetType() != "AUTOMATED" {
+		return diag.Errorf("farm %s is a %s farm, not an AUTOMATED farm", id, farmInfo.GetType())
+	}
+
+	if diags := setFarmCommonAttributes(d, &farmInfo); diags != nil {
+		return diags
+	}
+
+	if farmInfo.AutomatedFarmSettings != nil {
+		autoSettings := farmInfo.AutomatedFarmSettings
+		d.Set("enable_provisioning", autoSettings.EnableProvisioning)
+		d.Set("image_source", autoSettings.ImageSource)
+		d.Set("max_session_type", autoSettings.MaxSessionType)
+		d.Set("max_sessions", autoSettings.MaxSessions)
+		d.Set("min_ready_vms", autoSettings.MinReadyVms)
+		d.Set("stop_provisioning_on_error", autoSettings.StopProvisioningOnError)
+		d.Set("transparent_page_sharing_scope", autoSettings.TransparentPageSharingScope)
+		d.Set("vcenter_id", autoSettings.VcenterId)
+
+		if autoSettings.CustomizationSettings != nil {
+			if err := d.Set("clone_prep_settings", flattenFarmClonePrepSettings(autoSettings.CustomizationSettings)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		if autoSettings.PatternNamingSettings != nil {
+			patternNaming := map[string]interface{}{
+				"naming_pattern":            autoSettings.PatternNamingSettings.GetNamingPattern(),
+				"max_number_of_rds_servers": autoSettings.PatternNamingSettings.GetMaxNumberOfRdsServers(),
+			}
+			if err := d.Set("pattern_naming_settings", []interface{}{patternNaming}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		if autoSettings.ProvisioningSettings != nil {
+			if err := d.Set("provisioning_settings", flattenFarmProvisioningSettings(autoSettings.ProvisioningSettings)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		if autoSettings.StorageSettings != nil {
+			if err := d.Set("storage_settings", flattenFarmStorageSettings(autoSettings.StorageSettings)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setFarmCommonAttributes sets the attributes that are shared by all farm types.
+func setFarmCommonAttributes(d *schema.ResourceData, farmInfo *gohorizon.FarmInfoV3) diag.Diagnostics {
+	d.Set("access_group_id", farmInfo.AccessGroupId)
+	d.Set("delete_in_progress", farmInfo.DeleteInProgress)
+	d.Set("description", farmInfo.Description)
+	d.Set("desktop_pool_id", farmInfo.DesktopPoolId)
+	d.Set("display_name", farmInfo.DisplayName)
+	d.Set("enabled", farmInfo.Enabled)
+	d.Set("name", farmInfo.Name)
+	d.Set("server_error_threshold", farmInfo.ServerErrorThreshold)
+	d.Set("use_custom_script_for_load_balancing", farmInfo.UseCustomScriptForLoadBalancing)
+
+	if farmInfo.DisplayProtocolSettings != nil {
+		if err := d.Set("display_protocol_settings", flattenFarmDisplayProtocolSettings(farmInfo.DisplayProtocolSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if farmInfo.LoadBalancerSettings != nil {
+		if err := d.Set("load_balancer_settings", flattenFarmLoadBalancerSettings(farmInfo.LoadBalancerSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if farmInfo.SessionSettings != nil {
+		if err := d.Set("session_settings", flattenFarmSessionSettings(farmInfo.SessionSettings)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceFarmAutomatedUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	enableProvisioning := d.Get("enable_provisioning").(bool)
+	stopOnErr := d.Get("stop_provisioning_on_error").(bool)
+	maxSessionType := d.Get("max_session_type").(string)
+	minReady := int32(d.Get("min_ready_vms").(int))
+	tpsScope := d.Get("transparent_page_sharing_scope").(string)
+
+	clonePrep := d.Get("clone_prep_settings").([]interface{})[0].(map[string]interface{})
+	adRDN := clonePrep["ad_container_rdn"].(string)
+	reuse := clonePrep["reuse_pre_existing_accounts"].(bool)
+	pca := clonePrep["priming_computer_account"].(string)
+	psScriptName := clonePrep["post_synchronization_script_name"].(string)
+	psScriptParams := clonePrep["post_synchronization_script_parameters"].(string)
+	poScriptName := clonePrep["power_off_script_name"].(string)
+	poScriptParams := clonePrep["power_off_script_parameters"].(string)
+
+	clonePrepSettings := gohorizon.NewFarmCloneprepCustomizationSettingsUpdateSpec()
+	clonePrepSettings.PostSynchronizationScriptName = &psScriptName
+	clonePrepSettings.PostSynchronizationScriptParameters = &psScriptParams
+	clonePrepSettings.PowerOffScriptName = &poScriptName
+	clonePrepSettings.PowerOffScriptParameters = &poScriptParams
+	clonePrepSettings.PrimingComputerAccount = &pca
+
+	custSettings := gohorizon.NewFarmCustomizationSettingsUpdateSpec(adRDN, *clonePrepSettings, reuse)
+
+	patternNamingRaw := d.Get("pattern_naming_settings").([]interface{})[0].(map[string]interface{})
+	namingPattern := patternNamingRaw["naming_pattern"].(string)
+	maxServers := int32(patternNamingRaw["max_number_of_rds_servers"].(int))
+	patternNaming := gohorizon.NewFarmRDSServersPatternNamingSettingsUpdateSpec(maxServers, namingPattern)
+
+	provSettingsRaw := d.Get("provisioning_settings").([]interface{})[0].(map[string]interface{})
+	hcID := provSettingsRaw["host_or_cluster_id"].(string)
+	rpID := provSettingsRaw["resource_pool_id"].(string)
+	provSettings := gohorizon.NewFarmProvisioningSettingsUpdateSpec(hcID, rpID)
+
+	storSetRaw := d.Get("storage_settings").([]interface{})[0].(map[string]interface{})
+	datastores := []gohorizon.FarmDatastoreSettingsUpdateSpec{}
+	for _, dsID := range expandStringSet(storSetRaw["datastores"].(*schema.Set)) {
+		datastores = append(datastores, *gohorizon.NewFarmDatastoreSettingsUpdateSpec(dsID))
+	}
+	storageSettings := gohorizon.NewFarmStorageSettingsUpdateSpec(datastores)
+
+	if rddID := storSetRaw["replica_disk_datastore_id"].(string); rddID != "" {
+		storageSettings.ReplicaDiskDatastoreId = &rddID
+	}
+
+	autoSettings := gohorizon.NewFarmAutomatedSettingsUpdateSpec(*custSettings, enableProvisioning, maxSessionType, minReady, *patternNaming, *provSettings, stopOnErr, *storageSettings, tpsScope)
+
+	if maxSessionType == "LIMITED" {
+		maxSessions := int32(d.Get("max_sessions").(int))
+		autoSettings.MaxSessions = &maxSessions
+	}
+
+	body := expandFarmUpdateSpec(d)
+	body.AutomatedFarmSettings = autoSettings
+
+	resp, err := client.InventoryApi.UpdateFarm(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	return resourceFarmAutomatedRead(ctx, d, meta)
+}
+
+func resourceFarmDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	resp, err := client.InventoryApi.DeleteFarm(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// expandFarmUpdateSpec builds the parts of a farm update that are shared by all farm types.
+// The update replaces the whole farm configuration, so computed blocks fall back to their state.
+func expandFarmUpdateSpec(d *schema.ResourceData) *gohorizon.FarmUpdateSpec {
+	agID := d.Get("access_group_id").(string)
+	displayName := d.Get("display_name").(string)
+	enabled := d.Get("enabled").(bool)
+	errThreshold := int32(d.Get("server_error_threshold").(int))
+	customScript := d.Get("use_custom_script_for_load_balancing").(bool)
+	description := d.Get("description").(string)
+
+	dpSettings := expandFarmDisplayProtocolSettingsUpdateSpec(d.Get("display_protocol_settings").([]interface{}))
+	sessionSettings := expandFarmSessionSettingsUpdateSpec(d.Get("session_settings").([]interface{}))
+
+	body := gohorizon.NewFarmUpdateSpec(agID, displayName, *dpSettings, enabled, errThreshold, *sessionSettings, customScript)
+	body.Description = &description
+
+	if lbs := d.Get("load_balancer_settings").([]interface{}); len(lbs) > 0 && lbs[0] != nil {
+		body.LoadBalancerSettings = expandFarmLoadBalancerSettingsUpdateSpec(lbs)
+	}
+
+	return body
+}
+
+func expandFarmDisplayProtocolSettingsCreateSpec(raw []interface{}) *gohorizon.FarmDisplayProtocolSettingsCreateSpec {
+	dpSettingsRaw := raw[0].(map[string]interface{})
+	allowChoose := dpSettingsRaw["allow_users_to_choose_protocol"].(bool)
+	defaultProtocol := dpSettingsRaw["default_display_protocol"].(string)
+	gridVgpus := dpSettingsRaw["grid_vgpus_enabled"].(bool)
+	sessionCollab := dpSettingsRaw["session_collaboration_enabled"].(bool)
+
+	dpSettings := gohorizon.NewFarmDisplayProtocolSettingsCreateSpec()
+	dpSettings.AllowUsersToChooseProtocol = &allowChoose
+	dpSettings.DefaultDisplayProtocol = &defaultProtocol
+	dpSettings.GridVgpusEnabled = &gridVgpus
+	dpSettings.SessionCollaborationEnabled = &sessionCollab
+
+	return dpSettings
+}
+
+func expandFarmDisplayProtocolSettingsUpdateSpec(raw []interface{}) *gohorizon.FarmDisplayProtocolSettingsUpdateSpec {
+	if len(raw) == 0 || raw[0] == nil {
+		return gohorizon.NewFarmDisplayProtocolSettingsUpdateSpec(true, "PCOIP", false)
+	}
+
+	dpSettingsRaw := raw[0].(map[string]interface{})
+	allowChoose := dpSettingsRaw["allow_users_to_choose_protocol"].(bool)
+	defaultProtocol := dpSettingsRaw["default_display_protocol"].(string)
+	sessionCollab := dpSettingsRaw["session_collaboration_enabled"].(bool)
+
+	return gohorizon.NewFarmDisplayProtocolSettingsUpdateSpec(allowChoose, defaultProtocol, sessionCollab)
+}
+
+func expandFarmLoadBalancerSettingsCreateSpec(raw []interface{}) *gohorizon.RDSHLoadBalancerSettingsCreateSpec {
+	lbSettingsRaw := raw[0].(map[string]interface{})
+	cpu := int32(lbSettingsRaw["cpu_threshold"].(int))
+	diskQueue := int32(lbSettingsRaw["disk_queue_length_threshold"].(int))
+	diskRead := int32(lbSettingsRaw["disk_read_latency_threshold"].(int))
+	diskWrite := int32(lbSettingsRaw["disk_write_latency_threshold"].(int))
+	sessionCount := lbSettingsRaw["include_session_count"].(bool)
+	memory := int32(lbSettingsRaw["memory_threshold"].(int))
+
+	lbSettings := gohorizon.NewRDSHLoadBalancerSettingsCreateSpec()
+	lbSettings.CpuThreshold = &cpu
+	lbSettings.DiskQueueLengthThreshold = &diskQueue
+	lbSettings.DiskReadLatencyThreshold = &diskRead
+	lbSettings.DiskWriteLatencyThreshold = &diskWrite
+	lbSettings.IncludeSessionCount = &sessionCount
+	lbSettings.MemoryThreshold = &memory
+
+	return lbSettings
+}
+
+func expandFarmLoadBalancerSettingsUpdateSpec(raw []interface{}) *gohorizon.RDSHLoadBalancerSettingsUpdateSpec {
+	lbSettingsRaw := raw[0].(map[string]interface{})
+	cpu := int32(lbSettingsRaw["cpu_threshold"].(int))
+	diskQueue := int32(lbSettingsRaw["disk_queue_length_threshold"].(int))
+	diskRead := int32(lbSettingsRaw["disk_read_latency_threshold"].(int))
+	diskWrite := int32(lbSettingsRaw["disk_write_latency_threshold"].(int))
+	sessionCount := lbSettingsRaw["include_session_count"].(bool)
+	memory := int32(lbSettingsRaw["memory_threshold"].(int))
+
+	return gohorizon.NewRDSHLoadBalancerSettingsUpdateSpec(cpu, diskQueue, diskRead, diskWrite, sessionCount, memory)
+}
+
+func expandFarmSessionSettingsCreateSpec(raw []interface{}) *gohorizon.FarmSessionSettingsCreateSpec {
+	ssRaw := raw[0].(map[string]interface{})
+	disconnectedPolicy := ssRaw["disconnected_session_timeout_policy"].(string)
+	emptyPolicy := ssRaw["empty_session_timeout_policy"].(string)
+	emptyMinutes := int32(ssRaw["empty_session_timeout_minutes"].(int))
+	logoff := ssRaw["logoff_after_timeout"].(bool)
+	preLaunchPolicy := ssRaw["pre_launch_session_timeout_policy"].(string)
+	preLaunchMinutes := int32(ssRaw["pre_launch_session_timeout_minutes"].(int))
+
+	sessionSettings := gohorizon.NewFarmSessionSettingsCreateSpec()
+	sessionSettings.DisconnectedSessionTimeoutPolicy = &disconnectedPolicy
+	sessionSettings.EmptySessionTimeoutPolicy = &emptyPolicy
+	sessionSettings.LogoffAfterTimeout = &logoff
+	sessionSettings.PreLaunchSessionTimeoutPolicy = &preLaunchPolicy
+
+	if disconnectedPolicy == "AFTER" {
+		disconnectedMinutes := int32(ssRaw["disconnected_session_timeout_minutes"].(int))
+		sessionSettings.DisconnectedSessionTimeoutMinutes = &disconnectedMinutes
+	}
+
+	if emptyPolicy == "AFTER" {
+		sessionSettings.EmptySessionTimeoutMinutes = &emptyMinutes
+	}
+
+	if preLaunchPolicy == "AFTER" {
+		sessionSettings.PreLaunchSessionTimeoutMinutes = &preLaunchMinutes
+	}
+
+	return sessionSettings
+}
+
+func expandFarmSessionSettingsUpdateSpec(raw []interface{}) *gohorizon.FarmSessionSettingsUpdateSpec {
+	if len(raw) == 0 || raw[0] == nil {
+		return gohorizon.NewFarmSessionSettingsUpdateSpec("NEVER", "AFTER")
+	}
+
+	ssRaw := raw[0].(map[string]interface{})
+	disconnectedPolicy := ssRaw["disconnected_session_timeout_policy"].(string)
+	emptyPolicy := ssRaw["empty_session_timeout_policy"].(string)
+	emptyMinutes := int32(ssRaw["empty_session_timeout_minutes"].(int))
+	logoff := ssRaw["logoff_after_timeout"].(bool)
+	preLaunchPolicy := ssRaw["pre_launch_session_timeout_policy"].(string)
+	preLaunchMinutes := int32(ssRaw["pre_launch_session_timeout_minutes"].(int))
+
+	sessionSettings := gohorizon.NewFarmSessionSettingsUpdateSpec(disconnectedPolicy, emptyPolicy)
+	sessionSettings.LogoffAfterTimeout = &logoff
+	sessionSettings.PreLaunchSessionTimeoutPolicy = &preLaunchPolicy
+
+	if disconnectedPolicy == "AFTER" {
+		disconnectedMinutes := int32(ssRaw["disconnected_session_timeout_minutes"].(int))
+		sessionSettings.DisconnectedSessionTimeoutMinutes = &disconnectedMinutes
+	}
+
+	if emptyPolicy == "AFTER" {
+		sessionSettings.EmptySessionTimeoutMinutes = &emptyMinutes
+	}
+
+	if preLaunchPolicy == "AFTER" {
+		sessionSettings.PreLaunchSessionTimeoutMinutes = &preLaunchMinutes
+	}
+
+	return sessionSettings
+}
+
+func flattenFarmClonePrepSettings(custSettings *gohorizon.FarmCustomizationSettingsInfoV2) []interface{} {
+	clonePrep := map[string]interface{}{
+		"ad_container_rdn":                custSettings.GetAdContainerRdn(),
+		"instant_clone_domain_account_id": custSettings.GetInstantCloneDomainAccountId(),
+		"reuse_pre_existing_accounts":     custSettings.GetReusePreExistingAccounts(),
+	}
+
+	if custSettings.CloneprepCustomizationSettings != nil {
+		clonePrepSettings := custSettings.CloneprepCustomizationSettings
+		clonePrep["priming_computer_account"] = clonePrepSettings.GetPrimingComputerAccount()
+		clonePrep["post_synchronization_script_name"] = clonePrepSettings.GetPostSynchronizationScriptName()
+		clonePrep["post_synchronization_script_parameters"] = clonePrepSettings.GetPostSynchronizationScriptParameters()
+		clonePrep["power_off_script_name"] = clonePrepSettings.GetPowerOffScriptName()
+		clonePrep["power_off_script_parameters"] = clonePrepSettings.GetPowerOffScriptParameters()
+	}
+
+	return []interface{}{clonePrep}
+}
+
+func flattenFarmDisplayProtocolSettings(dpSettings *gohorizon.FarmDisplayProtocolSettingsInfo) []interface{} {
+	displayProtocol := map[string]interface{}{
+		"allow_users_to_choose_protocol": dpSettings.GetAllowUsersToChooseProtocol(),
+		"default_display_protocol":       dpSettings.GetDefaultDisplayProtocol(),
+		"grid_vgpus_enabled":             dpSettings.GetGridVgpusEnabled(),
+		"session_collaboration_enabled":  dpSettings.GetSessionCollaborationEnabled(),
+	}
+
+	return []interface{}{displayProtocol}
+}
+
+func flattenFarmLoadBalancerSettings(lbSettings *gohorizon.RDSHLoadBalancerSettingsInfo) []interface{} {
+	loadBalancer := map[string]interface{}{
+		"cpu_threshold":                lbSettings.GetCpuThreshold(),
+		"disk_queue_length_threshold":  lbSettings.GetDiskQueueLengthThreshold(),
+		"disk_read_latency_threshold":  lbSettings.GetDiskReadLatencyThreshold(),
+		"disk_write_latency_threshold": lbSettings.GetDiskWriteLatencyThreshold(),
+		"include_session_count":        lbSettings.GetIncludeSessionCount(),
+		"memory_threshold":             lbSettings.GetMemoryThreshold(),
+	}
+
+	return []interface{}{loadBalancer}
+}
+
+func flattenFarmProvisioningSettings(provSettings *gohorizon.FarmProvisioningSettingsInfo) []interface{} {
+	provisioning := map[string]interface{}{
+		"base_snapshot_id":   provSettings.GetBaseSnapshotId(),
+		"datacenter_id":      provSettings.GetDatacenterId(),
+		"host_or_cluster_id": provSettings.GetHostOrClusterId(),
+		"parent_vm_id":       provSettings.GetParentVmId(),
+		"resource_pool_id":   provSettings.GetResourcePoolId(),
+		"vm_folder_id":       provSettings.GetVmFolderId(),
+	}
+
+	return []interface{}{provisioning}
+}
+
+func flattenFarmSessionSettings(ssSettings *gohorizon.FarmSessionSettingsInfo) []interface{} {
+	session := map[string]interface{}{
+		"disconnected_session_timeout_minutes": ssSettings.GetDisconnectedSessionTimeoutMinutes(),
+		"disconnected_session_timeout_policy":  ssSettings.GetDisconnectedSessionTimeoutPolicy(),
+		"empty_session_timeout_minutes":        ssSettings.GetEmptySessionTimeoutMinutes(),
+		"empty_session_timeout_policy":         ssSettings.GetEmptySessionTimeoutPolicy(),
+		"logoff_after_timeout":                 ssSettings.GetLogoffAfterTimeout(),
+		"pre_launch_session_timeout_minutes":   ssSettings.GetPreLaunchSessionTimeoutMinutes(),
+		"pre_launch_session_timeout_policy":    ssSettings.GetPreLaunchSessionTimeoutPolicy(),
+	}
+
+	return []interface{}{session}
+}
+
+func flattenFarmStorageSettings(storSettings *gohorizon.FarmStorageSettingsInfo) []interface{} {
+	datastores := []interface{}{}
+	for _, datastore := range storSettings.GetDatastores() {
+		datastores = append(datastores, datastore.GetDatastoreId())
+	}
+
+	storage := map[string]interface{}{
+		"datastores":                   datastores,
+		"replica_disk_datastore_id":    storSettings.GetReplicaDiskDatastoreId(),
+		"use_view_storage_accelerator": storSettings.GetUseViewStorageAccelerator(),
+		"use_vsan":                     storSettings.GetUseVsan(),
+		"use_separate_datastores_replica_and_os_disks": storSettings.GetUseSeparateDatastoresReplicaAndOsDisks(),
+	}
+
+	return []interface{}{storage}
+}
@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testFarmAutomatedRaw returns the configuration of a valid automated farm merged with overrides.
+func testFarmAutomatedRaw(overrides map[string]interface{}) map[string]interface{} {
+	raw := map[string]interface{}{
+		"access_group_id": "access-group",
+		"name":            "rds-farm",
+		"vcenter_id":      "vcenter",
+		"clone_prep_settings": []interface{}{map[string]interface{}{
+			"ad_container_rdn":                "OU=RDS",
+			"instant_clone_domain_account_id": "account",
+		}},
+		"pattern_naming_settings": []interface{}{map[string]interface{}{
+			"naming_pattern":            "rds-{n}",
+			"max_number_of_rds_servers": 2,
+		}},
+		"provisioning_settings": []interface{}{map[string]interface{}{
+			"datacenter_id":      "datacenter",
+			"host_or_cluster_id": "cluster",
+			"resource_pool_id":   "resource-pool",
+			"vm_folder_id":       "folder",
+			"parent_vm_id":       "parent-vm",
+			"base_snapshot_id":   "snapshot",
+		}},
+		"storage_settings": []interface{}{map[string]interface{}{
+			"datastores": []interface{}{"datastore"},
+		}},
+	}
+	for k, v := range overrides {
+		raw[k] = v
+	}
+
+	return raw
+}
+
+func TestResourceFarmAutomatedPlan(t *testing.T) {
+	cases := []struct {
+		name        string
+		settings    string
+		storage     string
+		expectError string
+	}{
+		{
+			name: "valid farm",
+		},
+		{
+			name: "limited sessions",
+			settings: `max_session_type = "LIMITED"
+  max_sessions     = 50`,
+		},
+		{
+			name: "separate replica datastore",
+			storage: `replica_disk_datastore_id                    = "replica-datastore"
+    use_separate_datastores_replica_and_os_disks = true`,
+		},
+		{
+			name:        "limited sessions without max_sessions",
+			settings:    `max_session_type = "LIMITED"`,
+			expectError: `max_sessions must be set`,
+		},
+		{
+			name:        "unlimited sessions with max_sessions",
+			settings:    `max_sessions = 50`,
+			expectError: `max_sessions can only be set`,
+		},
+		{
+			name:        "separate datastores without replica datastore",
+			storage:     `use_separate_datastores_replica_and_os_disks = true`,
+			expectError: `replica_disk_datastore_id is not specified`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHorizonServer(t, nil)
+			testPlan(t, testProviderConfig(srv)+fmt.Sprintf(`
+resource "horizon_farm_automated" "test" {
+  access_group_id = "access-group"
+  name            = "rds-farm"
+  vcenter_id      = "vcenter"
+  %s
+
+  clone_prep_settings {
+    ad_container_rdn                = "OU=RDS"
+    instant_clone_domain_account_id = "account"
+  }
+
+  pattern_naming_settings {
+    naming_pattern            = "rds-{n}"
+    max_number_of_rds_servers = 2
+  }
+
+  provisioning_settings {
+    datacenter_id      = "datacenter"
+    host_or_cluster_id = "cluster"
+    resource_pool_id   = "resource-pool"
+    vm_folder_id       = "folder"
+    parent_vm_id       = "parent-vm"
+    base_snapshot_id   = "snapshot"
+  }
+
+  storage_settings {
+    datastores = ["datastore"]
+    %s
+  }
+}
+`, tc.settings, tc.storage), tc.expectError)
+		})
+	}
+}
+
+func TestResourceFarmAutomatedCreate(t *testing.T) {
+	cases := []struct {
+		name      string
+		overrides map[string]interface{}
+		check     func(t *testing.T, spec map[string]interface{})
+	}{
+		{
+			name: "limited sessions",
+			overrides: map[string]interface{}{
+				"max_session_type": "LIMITED",
+				"max_sessions":     50,
+			},
+			check: func(t *testing.T, spec map[string]interface{}) {
+				autoSettings := spec["automated_farm_settings"].(map[string]interface{})
+				if autoSettings["max_sessions"] != float64(50) {
+					t.Errorf("max_sessions = %v, want 50", autoSettings["max_sessions"])
+				}
+			},
+		},
+		{
+			name: "session timeouts",
+			overrides: map[string]interface{}{
+				"session_settings": []interface{}{map[string]interface{}{
+					"disconnected_session_timeout_policy":  "AFTER",
+					"disconnected_session_timeout_minutes": 30,
+					"empty_session_timeout_policy":         "NEVER",
+					"pre_launch_session_timeout_policy":    "AFTER",
+				}},
+			},
+			check: func(t *testing.T, spec map[string]interface{}) {
+				sessionSettings := spec["session_settings"].(map[string]interface{})
+				if sessionSettings["disconnected_session_timeout_minutes"] != float64(30) {
+					t.Errorf("disconnected_session_timeout_minutes = %v, want 30", sessionSettings["disconnected_session_timeout_minutes"])
+				}
+				if _, ok := sessionSettings["empty_session_timeout_minutes"]; ok {
+					t.Errorf("empty_session_timeout_minutes is sent although the policy is NEVER")
+				}
+				if sessionSettings["pre_launch_session_timeout_minutes"] != float64(10) {
+					t.Errorf("pre_launch_session_timeout_minutes = %v, want the default of 10", sessionSettings["pre_launch_session_timeout_minutes"])
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			farm := map[string]interface{}{"id": "farm", "name": "rds-farm", "type": "AUTOMATED"}
+
+			var spec map[string]interface{}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/farms": func(w http.ResponseWriter, r *http.Request) {
+					if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+						t.Errorf("error decoding the create spec: %s", err)
+					}
+					w.WriteHeader(http.StatusCreated)
+				},
+				"/rest/inventory/v3/farms": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, []interface{}{farm})
+				},
+				"/rest/inventory/v3/farms/farm": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, farm)
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceFarmAutomated().Schema, testFarmAutomatedRaw(tc.overrides))
+
+			diags := resourceFarmAutomatedCreate(context.Background(), d, testAPIClient(srv))
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if d.Id() != "farm" {
+				t.Errorf("ID = %q, want %q", d.Id(), "farm")
+			}
+			tc.check(t, spec)
+		})
+	}
+}
+
+func TestResourceFarmAutomatedRead(t *testing.T) {
+	testResourceRead(t, resourceFarmAutomated(), "farm", "/rest/inventory/v3/farms/farm", nil, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:      "manual farm",
+			object:    map[string]interface{}{"id": "farm", "type": "MANUAL"},
+			wantID:    "farm",
+			wantError: "not an AUTOMATED farm",
+		},
+		{
+			name: "automated farm",
+			object: map[string]interface{}{
+				"id":   "farm",
+				"type": "AUTOMATED",
+				"automated_farm_settings": map[string]interface{}{
+					"max_session_type": "UNLIMITED",
+					"pattern_naming_settings": map[string]interface{}{
+						"naming_pattern":            "rds-{n}",
+						"max_number_of_rds_servers": 4,
+					},
+				},
+			},
+			wantID: "farm",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := d.Get("pattern_naming_settings.0.max_number_of_rds_servers"); got != 4 {
+					t.Errorf("max_number_of_rds_servers = %v, want 4", got)
+				}
+			},
+		},
+	})
+}
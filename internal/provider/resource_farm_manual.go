@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceFarmManual() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage Horizon Manual RDS Farms. Manual farms are made up of RDS Servers that have already been registered with Horizon.",
+
+		CreateContext: resourceFarmManualCreate,
+		ReadContext:   resourceFarmManualRead,
+		UpdateContext: resourceFarmManualUpdate,
+		DeleteContext: resourceFarmDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"access_group_id": {
+				Description: "Access groups can organize the entities such as farms in the organization. They can also be used for delegated administration.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Name of the farm. This property must contain only alphanumerics, underscores, and dashes.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"rds_server_ids": {
+				Description: "IDs of the registered RDS Servers in the farm.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"description": {
+				Description: "Description of the farm.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"display_name": {
+				Description: "Display name of the farm. If the display name is left blank, it defaults to name.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"display_protocol_settings": farmDisplayProtocolSettingsSchema(),
+			"enabled": {
+				Description: "Indicates whether the farm is enabled for brokering.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"load_balancer_settings": farmLoadBalancerSettingsSchema(),
+			"server_error_threshold": {
+				Description: "The minimum number of RDS Servers that must be fully operational in order to avoid showing the farm in an error state.",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+			},
+			"session_settings": farmSessionSettingsSchema(),
+			"use_custom_script_for_load_balancing": {
+				Description: "Indicates whether to use custom scripts for load balancing.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"delete_in_progress": {
+				Description: "Indicates whether the farm is in the process of being deleted.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"desktop_pool_id": {
+				Description: "ID of the RDS desktop pool associated with the farm.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceFarmManualCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	farmType := "MANUAL"
+	agID := d.Get("access_group_id").(string)
+	enabled := d.Get("enabled").(bool)
+	errThreshold := int32(d.Get("server_error_threshold").(int))
+	customScript := d.Get("use_custom_script_for_load_balancing").(bool)
+	serverIDs := expandStringSet(d.Get("rds_server_ids").(*schema.Set))
+
+	body := gohorizon.NewFarmCreateSpec(agID, name, farmType)
+	body.Enabled = &enabled
+	body.RdsServerIds = &serverIDs
+	body.ServerErrorThreshold = &errThreshold
+	body.UseCustomScriptForLoadBalancing = &customScript
+
+	if dn, ok := d.GetOk("display_name"); ok {
+		displayName := dn.(string)
+		body.DisplayName = &displayName
+	}
+
+	if desc, ok := d.GetOk("description"); ok {
+		description := desc.(string)
+		body.Description = &description
+	}
+
+	if dps, ok := d.GetOk("display_protocol_settings"); ok {
+		body.DisplayProtocolSettings = expandFarmDisplayProtocolSettingsCreateSpec(dps.([]interface{}))
+	}
+
+	if lbs, ok := d.GetOk("load_balancer_settings"); ok {
+		body.LoadBalancerSettings = expandFarmLoadBalancerSettingsCreateSpec(lbs.([]interface{}))
+	}
+
+	if ss, ok := d.GetOk("session_settings"); ok {
+		body.SessionSettings = expandFarmSessionSettingsCreateSpec(ss.([]interface{}))
+	}
+
+	resp, err := client.InventoryApi.CreateFarm(ctx).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	id, diags := findFarmIDByName(ctx, &client, name)
+	if diags != nil {
+		return diags
+	}
+
+	d.SetId(id)
+	return resourceFarmManualRead(ctx, d, meta)
+}
+
+func resourceFarmManualRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	farmInfo, resp, err := client.InventoryApi.GetFarmV3(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the farm was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if farmInfo.GetType() != "MANUAL" {
+		return diag.Errorf("farm %s is a %s farm, not a MANUAL farm", id, farmInfo.GetType())
+	}
+
+	if diags := setFarmCommonAttributes(d, &farmInfo); diags != nil {
+		return diags
+	}
+
+	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"farm_id\",\"value\":\"%s\"}", id)
+	serverIDs := []string{}
+	for page := int32(1); ; page++ {
+		servers, resp, err := client.InventoryApi.ListRDSServers(ctx).Filter(filter).Page(page).Size(listPageSize).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+
+		for _, server := range servers {
+			serverIDs = append(serverIDs, server.GetId())
+		}
+
+		if len(servers) < listPageSize {
+			break
+		}
+	}
+	d.Set("rds_server_ids", serverIDs)
+
+	return nil
+}
+
+func resourceFarmManualUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	body := expandFarmUpdateSpec(d)
+
+	resp, err := client.InventoryApi.UpdateFarm(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if d.HasChange("rds_server_ids") {
+		if diags := resourceFarmManualUpdateRDSServers(ctx, d, &client); diags != nil {
+			return diags
+		}
+	}
+
+	return resourceFarmManualRead(ctx, d, meta)
+}
+
+// resourceFarmManualUpdateRDSServers adds and removes RDS Servers so the farm contains exactly the configured servers.
+func resourceFarmManualUpdateRDSServers(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient) diag.Diagnostics {
+	id := d.Id()
+
+	o, n := d.GetChange("rds_server_ids")
+	addList, removeList := diffStringSets(expandStringSet(o.(*schema.Set)), expandStringSet(n.(*schema.Set)))
+
+	// servers are added first so the farm is never left without servers
+	if len(addList) > 0 {
+		results, resp, err := client.InventoryApi.AddRdsServers(ctx, id).Body(addList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	if len(removeList) > 0 {
+		results, resp, err := client.InventoryApi.RemoveRdsServers(ctx, id).Body(removeList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	return nil
+}
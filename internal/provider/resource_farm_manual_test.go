@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceFarmManualRead(t *testing.T) {
+	testResourceRead(t, resourceFarmManual(), "farm", "/rest/inventory/v3/farms/farm", map[string]http.HandlerFunc{
+		"/rest/inventory/v1/rds-servers": func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.URL.Query().Get("filter"), `"value":"farm"`) {
+				t.Errorf("RDS servers filter = %q, want the farm", r.URL.Query().Get("filter"))
+			}
+			writeTestJSON(w, http.StatusOK, []map[string]string{{"id": "server-1"}, {"id": "server-2"}})
+		},
+	}, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:      "automated farm",
+			object:    map[string]interface{}{"id": "farm", "type": "AUTOMATED"},
+			wantID:    "farm",
+			wantError: "not a MANUAL farm",
+		},
+		{
+			name:   "manual farm",
+			object: map[string]interface{}{"id": "farm", "type": "MANUAL", "name": "rds-farm"},
+			wantID: "farm",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got, want := sortedStringSet(d.Get("rds_server_ids").(*schema.Set)), []string{"server-1", "server-2"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("rds_server_ids = %v, want %v", got, want)
+				}
+			},
+		},
+	})
+}
+
+func TestResourceFarmManualUpdateRDSServers(t *testing.T) {
+	farm := func(servers ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"access_group_id": "access-group",
+			"name":            "rds-farm",
+			"rds_server_ids":  servers,
+		}
+	}
+
+	cases := []struct {
+		name       string
+		state      map[string]interface{}
+		config     map[string]interface{}
+		failAdd    bool
+		wantCalls  []string
+		wantAdd    []string
+		wantRemove []string
+		wantError  string
+	}{
+		{
+			name:       "add and remove",
+			state:      farm("server-1", "server-2"),
+			config:     farm("server-2", "server-3"),
+			wantCalls:  []string{"add-rds-servers", "remove-rds-servers"},
+			wantAdd:    []string{"server-3"},
+			wantRemove: []string{"server-1"},
+		},
+		{
+			name:       "remove only",
+			state:      farm("server-1", "server-2"),
+			config:     farm("server-2"),
+			wantCalls:  []string{"remove-rds-servers"},
+			wantRemove: []string{"server-1"},
+		},
+		{
+			name:      "failed add",
+			state:     farm("server-1"),
+			config:    farm("server-2"),
+			failAdd:   true,
+			wantCalls: []string{"add-rds-servers"},
+			wantAdd:   []string{"server-2"},
+			wantError: "operation failed for server-2 with status 404",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls, added, removed []string
+			bulkHandler := func(action string, ids *[]string, fail bool) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, action)
+					if err := json.NewDecoder(r.Body).Decode(ids); err != nil {
+						t.Errorf("error decoding the %s request: %s", action, err)
+					}
+					sort.Strings(*ids)
+
+					results := []map[string]interface{}{}
+					for _, id := range *ids {
+						result := map[string]interface{}{"id": id, "status_code": http.StatusOK}
+						if fail {
+							result["status_code"] = http.StatusNotFound
+							result["error_messages"] = []string{"RDS server not found"}
+						}
+						results = append(results, result)
+					}
+					writeTestJSON(w, http.StatusOK, results)
+				}
+			}
+
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/farms/farm/action/add-rds-servers":    bulkHandler("add-rds-servers", &added, tc.failAdd),
+				"/rest/inventory/v1/farms/farm/action/remove-rds-servers": bulkHandler("remove-rds-servers", &removed, false),
+			})
+
+			d := testResourceDataUpdate(t, resourceFarmManual(), "farm", tc.state, tc.config)
+			client := testAPIClient(srv).Client
+
+			diags := resourceFarmManualUpdateRDSServers(context.Background(), d, &client)
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(calls, tc.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tc.wantCalls)
+			}
+			if !reflect.DeepEqual(added, tc.wantAdd) {
+				t.Errorf("added = %v, want %v", added, tc.wantAdd)
+			}
+			if !reflect.DeepEqual(removed, tc.wantRemove) {
+				t.Errorf("removed = %v, want %v", removed, tc.wantRemove)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceGlobalApplicationEntitlement() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage Cloud Pod Architecture Global Application Entitlements. Use horizon_global_entitlement_users to entitle users and groups to the Global Application Entitlement.",
+
+		CreateContext: resourceGlobalApplicationEntitlementCreate,
+		ReadContext:   resourceGlobalApplicationEntitlementRead,
+		UpdateContext: resourceGlobalApplicationEntitlementUpdate,
+		DeleteContext: resourceGlobalApplicationEntitlementDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"federated_access_group_id": {
+				Description: "ID of the federated access group with which the Global Application Entitlement is associated.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Unique name used to identify the Global Application Entitlement.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"allow_users_to_choose_protocol": {
+				Description: "Indicates whether the users can choose the protocol used. This can only be set when the Global Application Entitlement is created.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+			},
+			"category_folder_name": {
+				Description: "Name of the category folder in the user's OS containing a shortcut to the entitlement. Unset if the entitlement does not belong to a category.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"cs_restriction_tags": {
+				Description: "List of Connection server restriction tags to which the access to the Global Application Entitlement is restricted. If this property is not set it indicates that the entitlement can be accessed from any connection server.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"default_display_protocol": {
+				Description:  "The default display protocol for the Global Application Entitlement. Clients connecting through the Global Application Entitlement that do not specify a protocol will use this value.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "PCOIP",
+				ValidateFunc: validation.StringInSlice([]string{"PCOIP", "RDP", "BLAST"}, false),
+			},
+			"description": {
+				Description: "Description of the Global Application Entitlement.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"display_name": {
+				Description: "Name that users will see when they connect using Horizon Client. If display_name is left blank, it defaults to name.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"enable_client_restrictions": {
+				Description: "Indicates whether client restrictions are applied to the Global Application Entitlement.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"enable_pre_launch": {
+				Description: "Indicates whether the application can be pre-launched.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"enabled": {
+				Description: "Indicates whether the Global Application Entitlement is enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"local_application_pool_ids": {
+				Description: "IDs of the application pools in this pod that are members of the Global Application Entitlement.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"multi_session_mode": {
+				Description:  "Multi-session mode for the Global Application Entitlement. DISABLED: Multi-session is not supported. ENABLED_DEFAULT_OFF: Multi-session is supported but is disabled by default. ENABLED_DEFAULT_ON: Multi-session is supported and is enabled by default. ENABLED_ENFORCED: Multi-session is supported and it is enforced.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "DISABLED",
+				ValidateFunc: validation.StringInSlice([]string{"DISABLED", "ENABLED_DEFAULT_OFF", "ENABLED_DEFAULT_ON", "ENABLED_ENFORCED"}, false),
+			},
+			"multiple_session_auto_clean": {
+				Description: "Indicates whether automatic session clean up is enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"require_home_site": {
+				Description: "Indicates whether to fail the request if a home site isn't defined for the user.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"scope": {
+				Description:  "Scope for the Global Application Entitlement. Visibility and placement policies are defined by this value. WITHIN_POD: Only the local pod is used. WITHIN_SITE: Only pods in the local site are used. ALL_SITES: Any pod can be used.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ALL_SITES",
+				ValidateFunc: validation.StringInSlice([]string{"WITHIN_POD", "WITHIN_SITE", "ALL_SITES"}, false),
+			},
+			"shortcut_locations_v2": {
+				Description: "Locations of the category folder in the user's OS containing a shortcut to the application. This is required if category_folder_name is set.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"START_MENU", "DESKTOP"}, false),
+				},
+			},
+			"use_home_site": {
+				Description: "Indicates whether a pod in the user's home site is used to start the search or the current site is used.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"group_count": {
+				Description: "Number of groups entitled to the Global Application Entitlement.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"member_pods": {
+				Description: "IDs of the pods that have application pools associated with the Global Application Entitlement.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"user_count": {
+				Description: "Number of users entitled to the Global Application Entitlement.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceGlobalApplicationEntitlementCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	fagID := d.Get("federated_access_group_id").(string)
+	chooseProtocol := d.Get("allow_users_to_choose_protocol").(bool)
+	defaultProtocol := d.Get("default_display_protocol").(string)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	preLaunch := d.Get("enable_pre_launch").(bool)
+	enabled := d.Get("enabled").(bool)
+	multiSessionMode := d.Get("multi_session_mode").(string)
+	autoClean := d.Get("multiple_session_auto_clean").(bool)
+	requireHomeSite := d.Get("require_home_site").(bool)
+	scope := d.Get("scope").(string)
+	useHomeSite := d.Get("use_home_site").(bool)
+
+	body := gohorizon.NewGlobalApplicationEntitlementCreateSpec(fagID, name)
+	body.AllowUsersToChooseProtocol = &chooseProtocol
+	body.DefaultDisplayProtocol = &defaultProtocol
+	body.EnableClientRestrictions = &clientRestrictions
+	body.EnablePreLaunch = &preLaunch
+	body.Enabled = &enabled
+	body.MultiSessionMode = &multiSessionMode
+	body.MultipleSessionAutoClean = &autoClean
+	body.RequireHomeSite = &requireHomeSite
+	body.Scope = &scope
+	body.UseHomeSite = &useHomeSite
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	if tags, ok := d.GetOk("cs_restriction_tags"); ok {
+		csTags := expandStringSet(tags.(*schema.Set))
+		body.CsRestrictionTags = &csTags
+	}
+
+	if desc, ok := d.GetOk("description"); ok {
+		description := desc.(string)
+		body.Description = &description
+	}
+
+	if dn, ok := d.GetOk("display_name"); ok {
+		displayName := dn.(string)
+		body.DisplayName = &displayName
+	}
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	resp, err := client.InventoryApi.CreateGlobalApplicationEntitlement(ctx).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"name\",\"value\":\"%s\"}", name)
+	gaes, resp, err := client.InventoryApi.ListGlobalApplicationEntitlementsV2(ctx).Filter(filter).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	switch len(gaes) {
+	case 0:
+		return diag.Errorf("could not find ID of global application entitlement that was created")
+	case 1:
+		d.SetId(*gaes[0].Id)
+	default:
+		return diag.Errorf("Multiple global application entitlements found with same name - should not be possible")
+	}
+
+	if diags := resourceGlobalApplicationEntitlementUpdatePools(ctx, d, &client); diags != nil {
+		return diags
+	}
+
+	return resourceGlobalApplicationEntitlementRead(ctx, d, meta)
+}
+
+func resourceGlobalApplicationEntitlementRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	gae, resp, err := client.InventoryApi.GetGlobalApplicationEntitlementV2(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the global application entitlement was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.Set("allow_users_to_choose_protocol", gae.AllowUsersToChooseProtocol)
+	d.Set("category_folder_name", gae.CategoryFolderName)
+	d.Set("cs_restriction_tags", gae.CsRestrictionTags)
+	d.Set("default_display_protocol", gae.DefaultDisplayProtocol)
+	d.Set("description", gae.Description)
+	d.Set("display_name", gae.DisplayName)
+	d.Set("enable_client_restrictions", gae.EnableClientRestrictions)
+	d.Set("enable_pre_launch", gae.EnablePreLaunch)
+	d.Set("enabled", gae.Enabled)
+	d.Set("federated_access_group_id", gae.FederatedAccessGroupId)
+	d.Set("group_count", gae.GroupCount)
+	d.Set("member_pods", gae.MemberPods)
+	d.Set("multi_session_mode", gae.MultiSessionMode)
+	d.Set("multiple_session_auto_clean", gae.MultipleSessionAutoClean)
+	d.Set("name", gae.Name)
+	d.Set("require_home_site", gae.RequireHomeSite)
+	d.Set("scope", gae.Scope)
+	d.Set("shortcut_locations_v2", gae.ShortcutLocationsV2)
+	d.Set("use_home_site", gae.UseHomeSite)
+	d.Set("user_count", gae.UserCount)
+
+	appPoolIDs, resp, err := client.InventoryApi.ListLocalApplicationPools(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	d.Set("local_application_pool_ids", appPoolIDs)
+
+	return nil
+}
+
+func resourceGlobalApplicationEntitlementUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	name := d.Get("name").(string)
+	fagID := d.Get("federated_access_group_id").(string)
+	defaultProtocol := d.Get("default_display_protocol").(string)
+	displayName := d.Get("display_name").(string)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	preLaunch := d.Get("enable_pre_launch").(bool)
+	enabled := d.Get("enabled").(bool)
+	multiSessionMode := d.Get("multi_session_mode").(string)
+	autoClean := d.Get("multiple_session_auto_clean").(bool)
+	requireHomeSite := d.Get("require_home_site").(bool)
+	scope := d.Get("scope").(string)
+	useHomeSite := d.Get("use_home_site").(bool)
+	description := d.Get("description").(string)
+
+	body := gohorizon.NewGlobalApplicationEntitlementUpdateSpec(defaultProtocol, displayName, clientRestrictions, preLaunch, enabled, fagID, multiSessionMode, autoClean, name, requireHomeSite, scope, useHomeSite)
+	body.Description = &description
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	csTags := expandStringSet(d.Get("cs_restriction_tags").(*schema.Set))
+	body.CsRestrictionTags = &csTags
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	resp, err := client.InventoryApi.UpdateGlobalApplicationEntitlement(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if d.HasChange("local_application_pool_ids") {
+		if diags := resourceGlobalApplicationEntitlementUpdatePools(ctx, d, &client); diags != nil {
+			return diags
+		}
+	}
+
+	return resourceGlobalApplicationEntitlementRead(ctx, d, meta)
+}
+
+// resourceGlobalApplicationEntitlementUpdatePools reconciles the local application pools that are members of the Global Application Entitlement.
+func resourceGlobalApplicationEntitlementUpdatePools(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient) diag.Diagnostics {
+	id := d.Id()
+	appPoolIDs := expandStringSet(d.Get("local_application_pool_ids").(*schema.Set))
+
+	currentAppPoolIDs, resp, err := client.InventoryApi.ListLocalApplicationPools(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	addList, removeList := diffStringSets(currentAppPoolIDs, appPoolIDs)
+
+	if len(removeList) > 0 {
+		results, resp, err := client.InventoryApi.RemoveLocalApplicationPoolsFromGAE(ctx, id).Body(removeList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	if len(addList) > 0 {
+		results, resp, err := client.InventoryApi.AddLocalApplicationPoolsToGAE(ctx, id).Body(addList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	return nil
+}
+
+func resourceGlobalApplicationEntitlementDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	// member application pools have to be removed before the Global Application Entitlement can be deleted
+	appPoolIDs, resp, err := client.InventoryApi.ListLocalApplicationPools(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if len(appPoolIDs) > 0 {
+		results, resp, err := client.InventoryApi.RemoveLocalApplicationPoolsFromGAE(ctx, id).Body(appPoolIDs).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	resp, err = client.InventoryApi.DeleteGlobalApplicationEntitlement(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceGlobalApplicationEntitlementUpdatePools(t *testing.T) {
+	cases := []struct {
+		name      string
+		failing   map[string]bool
+		wantError string
+		wantPools []string
+	}{
+		{
+			name:      "success",
+			wantPools: []string{"app-b", "app-c"},
+		},
+		{
+			name:      "failed add",
+			failing:   map[string]bool{"app-c": true},
+			wantError: "operation failed for app-c with status 409",
+			wantPools: []string{"app-b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pools := []string{"app-a", "app-b"}
+			var calls []string
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/global-application-entitlements/gae/local-application-pools": testLocalPoolsHandler(t, &pools, &calls, tc.failing),
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceGlobalApplicationEntitlement().Schema, map[string]interface{}{
+				"local_application_pool_ids": []interface{}{"app-b", "app-c"},
+			})
+			d.SetId("gae")
+			client := testAPIClient(srv).Client
+
+			diags := resourceGlobalApplicationEntitlementUpdatePools(context.Background(), d, &client)
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(pools, tc.wantPools) {
+				t.Errorf("local application pools = %v, want %v", pools, tc.wantPools)
+			}
+		})
+	}
+}
+
+func TestResourceGlobalApplicationEntitlementRead(t *testing.T) {
+	testResourceRead(t, resourceGlobalApplicationEntitlement(), "gae", "/rest/inventory/v2/global-application-entitlements/gae", map[string]http.HandlerFunc{
+		"/rest/inventory/v1/global-application-entitlements/gae/local-application-pools": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []string{"app-a"})
+		},
+	}, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:   "global application entitlement",
+			object: map[string]interface{}{"id": "gae", "name": "notepad", "scope": "ANY"},
+			wantID: "gae",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got, want := sortedStringSet(d.Get("local_application_pool_ids").(*schema.Set)), []string{"app-a"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("local_application_pool_ids = %v, want %v", got, want)
+				}
+			},
+		},
+	})
+}
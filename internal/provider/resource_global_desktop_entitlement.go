@@ -0,0 +1,452 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceGlobalDesktopEntitlement() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource to manage Cloud Pod Architecture Global Desktop Entitlements. Use horizon_global_entitlement_users to entitle users and groups to the Global Desktop Entitlement.",
+
+		CreateContext: resourceGlobalDesktopEntitlementCreate,
+		ReadContext:   resourceGlobalDesktopEntitlementRead,
+		UpdateContext: resourceGlobalDesktopEntitlementUpdate,
+		DeleteContext: resourceGlobalDesktopEntitlementDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"federated_access_group_id": {
+				Description: "ID of the federated access group with which the Global Desktop Entitlement is associated.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"name": {
+				Description: "Unique name used to identify the Global Desktop Entitlement.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"allow_multiple_sessions_per_user": {
+				Description: "Indicates whether users can have multiple sessions when accessed from different client devices. This is only applicable to floating user assignment and the desktop pools associated with the Global Desktop Entitlement must also allow multiple sessions per user.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"allow_users_to_choose_protocol": {
+				Description: "Indicates whether the users can choose the protocol used. If true, the desktop pools associated with the Global Desktop Entitlement must also allow users to choose the display protocol. This can only be set when the Global Desktop Entitlement is created.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+			},
+			"allow_users_to_reset_machines": {
+				Description: "Indicates whether users are allowed to reset/restart their machines. If true, the desktop pools associated with the Global Desktop Entitlement must also allow users to reset machines. This can only be set when the Global Desktop Entitlement is created.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"category_folder_name": {
+				Description: "Name of the category folder in the user's OS containing a shortcut to the entitlement. Unset if the entitlement does not belong to a category.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"cloud_managed": {
+				Description: "Indicates whether the Global Desktop Entitlement is managed from Horizon Cloud Services.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"cs_restriction_tags": {
+				Description: "List of Connection server restriction tags to which the access to the Global Desktop Entitlement is restricted. If this property is not set it indicates that the entitlement can be accessed from any connection server.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"dedicated": {
+				Description: "Indicates whether the Global Desktop Entitlement is dedicated. If so, only dedicated desktop pools can be associated with it. Otherwise, only floating desktop pools can be associated with it.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"default_display_protocol": {
+				Description:  "The default display protocol for the Global Desktop Entitlement. Clients connecting through the Global Desktop Entitlement that do not specify a protocol will use this value.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "PCOIP",
+				ValidateFunc: validation.StringInSlice([]string{"PCOIP", "RDP", "BLAST"}, false),
+			},
+			"description": {
+				Description: "Description of the Global Desktop Entitlement.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"display_assigned_machine_name": {
+				Description: "Indicates whether users should see the hostname of the machine assigned to them instead of display_name when they connect using Horizon Client. This is applicable for dedicated Global Desktop Entitlements only.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"display_machine_alias": {
+				Description: "Indicates whether users should see the alias of the machine assigned to them instead of display_name when they connect using Horizon Client. This is applicable for dedicated Global Desktop Entitlements only.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"display_name": {
+				Description: "Name that users will see when they connect using Horizon Client. If display_name is left blank, it defaults to name.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"enable_client_restrictions": {
+				Description: "Indicates whether client restrictions are applied to the Global Desktop Entitlement. This is only valid for RDSH pools.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"enabled": {
+				Description: "Indicates whether the Global Desktop Entitlement is enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"local_desktop_pool_ids": {
+				Description: "IDs of the desktop pools in this pod that are members of the Global Desktop Entitlement.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"multiple_session_auto_clean": {
+				Description: "Indicates whether automatic session clean up is enabled. This cannot be enabled when the Global Desktop Entitlement is associated with a desktop pool that has dedicated user assignment.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"require_home_site": {
+				Description: "Indicates whether to fail the request if a home site isn't defined for the user.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"scope": {
+				Description:  "Scope for the Global Desktop Entitlement. Visibility and placement policies are defined by this value. WITHIN_POD: Only the local pod is used. WITHIN_SITE: Only pods in the local site are used. ALL_SITES: Any pod can be used.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ALL_SITES",
+				ValidateFunc: validation.StringInSlice([]string{"WITHIN_POD", "WITHIN_SITE", "ALL_SITES"}, false),
+			},
+			"session_collaboration_enabled": {
+				Description: "Indicates whether session collaboration is enabled. The desktop pools associated with the Global Desktop Entitlement must also have session collaboration enabled.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"shortcut_locations_v2": {
+				Description: "Locations of the category folder in the user's OS containing a shortcut to the desktop. This is required if category_folder_name is set.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"START_MENU", "DESKTOP"}, false),
+				},
+			},
+			"use_home_site": {
+				Description: "Indicates whether a pod in the user's home site is used to start the search or the current site is used.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"group_count": {
+				Description: "Number of groups entitled to the Global Desktop Entitlement.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"member_pods": {
+				Description: "IDs of the pods that have desktop pools associated with the Global Desktop Entitlement.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"user_count": {
+				Description: "Number of users entitled to the Global Desktop Entitlement.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceGlobalDesktopEntitlementCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	name := d.Get("name").(string)
+	fagID := d.Get("federated_access_group_id").(string)
+	multiSession := d.Get("allow_multiple_sessions_per_user").(bool)
+	chooseProtocol := d.Get("allow_users_to_choose_protocol").(bool)
+	resetMachines := d.Get("allow_users_to_reset_machines").(bool)
+	cloudManaged := d.Get("cloud_managed").(bool)
+	dedicated := d.Get("dedicated").(bool)
+	defaultProtocol := d.Get("default_display_protocol").(string)
+	displayAssigned := d.Get("display_assigned_machine_name").(bool)
+	displayAlias := d.Get("display_machine_alias").(bool)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	enabled := d.Get("enabled").(bool)
+	autoClean := d.Get("multiple_session_auto_clean").(bool)
+	requireHomeSite := d.Get("require_home_site").(bool)
+	scope := d.Get("scope").(string)
+	sessionCollab := d.Get("session_collaboration_enabled").(bool)
+	useHomeSite := d.Get("use_home_site").(bool)
+
+	body := gohorizon.NewGlobalDesktopEntitlementCreateSpecV2(fagID, name)
+	body.AllowMultipleSessionsPerUser = &multiSession
+	body.AllowUsersToChooseProtocol = &chooseProtocol
+	body.AllowUsersToResetMachines = &resetMachines
+	body.CloudManaged = &cloudManaged
+	body.Dedicated = &dedicated
+	body.DefaultDisplayProtocol = &defaultProtocol
+	body.DisplayAssignedMachineName = &displayAssigned
+	body.DisplayMachineAlias = &displayAlias
+	body.EnableClientRestrictions = &clientRestrictions
+	body.Enabled = &enabled
+	body.MultipleSessionAutoClean = &autoClean
+	body.RequireHomeSite = &requireHomeSite
+	body.Scope = &scope
+	body.SessionCollaborationEnabled = &sessionCollab
+	body.UseHomeSite = &useHomeSite
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	if tags, ok := d.GetOk("cs_restriction_tags"); ok {
+		csTags := expandStringSet(tags.(*schema.Set))
+		body.CsRestrictionTags = &csTags
+	}
+
+	if desc, ok := d.GetOk("description"); ok {
+		description := desc.(string)
+		body.Description = &description
+	}
+
+	if dn, ok := d.GetOk("display_name"); ok {
+		displayName := dn.(string)
+		body.DisplayName = &displayName
+	}
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	resp, err := client.InventoryApi.CreateGlobalDesktopEntitlementV2(ctx).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	filter := fmt.Sprintf("{\"type\":\"Equals\",\"name\":\"name\",\"value\":\"%s\"}", name)
+	gdes, resp, err := client.InventoryApi.ListGlobalDesktopEntitlementsV2(ctx).Filter(filter).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	switch len(gdes) {
+	case 0:
+		return diag.Errorf("could not find ID of global desktop entitlement that was created")
+	case 1:
+		d.SetId(*gdes[0].Id)
+	default:
+		return diag.Errorf("Multiple global desktop entitlements found with same name - should not be possible")
+	}
+
+	if diags := resourceGlobalDesktopEntitlementUpdatePools(ctx, d, &client); diags != nil {
+		return diags
+	}
+
+	return resourceGlobalDesktopEntitlementRead(ctx, d, meta)
+}
+
+func resourceGlobalDesktopEntitlementRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	gde, resp, err := client.InventoryApi.GetGlobalDesktopEntitlementV2(ctx, id).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the global desktop entitlement was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.Set("allow_multiple_sessions_per_user", gde.AllowMultipleSessionsPerUser)
+	d.Set("allow_users_to_choose_protocol", gde.AllowUsersToChooseProtocol)
+	d.Set("allow_users_to_reset_machines", gde.AllowUsersToResetMachines)
+	d.Set("category_folder_name", gde.CategoryFolderName)
+	d.Set("cloud_managed", gde.CloudManaged)
+	d.Set("cs_restriction_tags", gde.CsRestrictionTags)
+	d.Set("dedicated", gde.Dedicated)
+	d.Set("default_display_protocol", gde.DefaultDisplayProtocol)
+	d.Set("description", gde.Description)
+	d.Set("display_assigned_machine_name", gde.DisplayAssignedMachineName)
+	d.Set("display_machine_alias", gde.DisplayMachineAlias)
+	d.Set("display_name", gde.DisplayName)
+	d.Set("enable_client_restrictions", gde.EnableClientRestrictions)
+	d.Set("enabled", gde.Enabled)
+	d.Set("federated_access_group_id", gde.FederatedAccessGroupId)
+	d.Set("group_count", gde.GroupCount)
+	d.Set("member_pods", gde.MemberPods)
+	d.Set("multiple_session_auto_clean", gde.MultipleSessionAutoClean)
+	d.Set("name", gde.Name)
+	d.Set("require_home_site", gde.RequireHomeSite)
+	d.Set("scope", gde.Scope)
+	d.Set("session_collaboration_enabled", gde.SessionCollaborationEnabled)
+	d.Set("shortcut_locations_v2", gde.ShortcutLocationsV2)
+	d.Set("use_home_site", gde.UseHomeSite)
+	d.Set("user_count", gde.UserCount)
+
+	poolIDs, resp, err := client.InventoryApi.ListLocalDesktopPools(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	d.Set("local_desktop_pool_ids", poolIDs)
+
+	return nil
+}
+
+func resourceGlobalDesktopEntitlementUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	name := d.Get("name").(string)
+	fagID := d.Get("federated_access_group_id").(string)
+	multiSession := d.Get("allow_multiple_sessions_per_user").(bool)
+	cloudManaged := d.Get("cloud_managed").(bool)
+	defaultProtocol := d.Get("default_display_protocol").(string)
+	displayAssigned := d.Get("display_assigned_machine_name").(bool)
+	displayAlias := d.Get("display_machine_alias").(bool)
+	displayName := d.Get("display_name").(string)
+	clientRestrictions := d.Get("enable_client_restrictions").(bool)
+	enabled := d.Get("enabled").(bool)
+	autoClean := d.Get("multiple_session_auto_clean").(bool)
+	requireHomeSite := d.Get("require_home_site").(bool)
+	scope := d.Get("scope").(string)
+	sessionCollab := d.Get("session_collaboration_enabled").(bool)
+	useHomeSite := d.Get("use_home_site").(bool)
+	description := d.Get("description").(string)
+
+	body := gohorizon.NewGlobalDesktopEntitlementUpdateSpec(multiSession, cloudManaged, defaultProtocol, displayAssigned, displayAlias, displayName, clientRestrictions, enabled, fagID, autoClean, name, requireHomeSite, scope, sessionCollab, useHomeSite)
+	body.Description = &description
+
+	if cfn, ok := d.GetOk("category_folder_name"); ok {
+		categoryFolder := cfn.(string)
+		body.CategoryFolderName = &categoryFolder
+	}
+
+	csTags := expandStringSet(d.Get("cs_restriction_tags").(*schema.Set))
+	body.CsRestrictionTags = &csTags
+
+	if sl, ok := d.GetOk("shortcut_locations_v2"); ok {
+		shortcutLocations := expandStringSet(sl.(*schema.Set))
+		body.ShortcutLocationsV2 = &shortcutLocations
+	}
+
+	resp, err := client.InventoryApi.UpdateGlobalDesktopEntitlement(ctx, id).Body(*body).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if d.HasChange("local_desktop_pool_ids") {
+		if diags := resourceGlobalDesktopEntitlementUpdatePools(ctx, d, &client); diags != nil {
+			return diags
+		}
+	}
+
+	return resourceGlobalDesktopEntitlementRead(ctx, d, meta)
+}
+
+// resourceGlobalDesktopEntitlementUpdatePools reconciles the local desktop pools that are members of the Global Desktop Entitlement.
+func resourceGlobalDesktopEntitlementUpdatePools(ctx context.Context, d *schema.ResourceData, client *gohorizon.APIClient) diag.Diagnostics {
+	id := d.Id()
+	poolIDs := expandStringSet(d.Get("local_desktop_pool_ids").(*schema.Set))
+
+	currentPoolIDs, resp, err := client.InventoryApi.ListLocalDesktopPools(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	addList, removeList := diffStringSets(currentPoolIDs, poolIDs)
+
+	if len(removeList) > 0 {
+		results, resp, err := client.InventoryApi.RemoveLocalDesktopPoolsFromGDE(ctx, id).Body(removeList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	if len(addList) > 0 {
+		results, resp, err := client.InventoryApi.AddLocalDesktopPoolsToGDE(ctx, id).Body(addList).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	return nil
+}
+
+func resourceGlobalDesktopEntitlementDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	id := d.Id()
+
+	// member desktop pools have to be removed before the Global Desktop Entitlement can be deleted
+	poolIDs, resp, err := client.InventoryApi.ListLocalDesktopPools(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	if len(poolIDs) > 0 {
+		results, resp, err := client.InventoryApi.RemoveLocalDesktopPoolsFromGDE(ctx, id).Body(poolIDs).Execute()
+		if err != nil {
+			return returnResponseErr(resp, err)
+		}
+		if diags := bulkItemResponseErrors(results); diags != nil {
+			return diags
+		}
+	}
+
+	resp, err = client.InventoryApi.DeleteGlobalDesktopEntitlement(ctx, id).Execute()
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
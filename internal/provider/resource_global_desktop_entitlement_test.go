@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testLocalPoolsHandler serves the local pools of a global entitlement, which are listed, added and
+// removed on the same path. Adding or removing the pools in failing fails.
+func testLocalPoolsHandler(t *testing.T, pools *[]string, calls *[]string, failing map[string]bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls = append(*calls, r.Method)
+		if r.Method == http.MethodGet {
+			writeTestJSON(w, http.StatusOK, *pools)
+			return
+		}
+
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			t.Errorf("error decoding the local pools: %s", err)
+		}
+
+		results := []map[string]interface{}{}
+		for _, id := range ids {
+			if failing[id] {
+				results = append(results, map[string]interface{}{"id": id, "status_code": http.StatusConflict, "error_messages": []string{"Pool is not compatible"}})
+				continue
+			}
+			results = append(results, map[string]interface{}{"id": id, "status_code": http.StatusOK})
+
+			remaining := []string{}
+			for _, pool := range *pools {
+				if pool != id {
+					remaining = append(remaining, pool)
+				}
+			}
+			if r.Method == http.MethodPost {
+				remaining = append(remaining, id)
+			}
+			sort.Strings(remaining)
+			*pools = remaining
+		}
+
+		writeTestJSON(w, http.StatusOK, results)
+	}
+}
+
+func TestResourceGlobalDesktopEntitlementUpdatePools(t *testing.T) {
+	cases := []struct {
+		name      string
+		failing   map[string]bool
+		wantError string
+		wantCalls []string
+		wantPools []string
+	}{
+		{
+			name:      "success",
+			wantCalls: []string{http.MethodGet, http.MethodDelete, http.MethodPost},
+			wantPools: []string{"pool-b", "pool-c"},
+		},
+		{
+			name:      "failed add",
+			failing:   map[string]bool{"pool-c": true},
+			wantError: "operation failed for pool-c with status 409",
+			wantCalls: []string{http.MethodGet, http.MethodDelete, http.MethodPost},
+			wantPools: []string{"pool-b"},
+		},
+		{
+			name:      "failed removal",
+			failing:   map[string]bool{"pool-a": true},
+			wantError: "operation failed for pool-a with status 409",
+			wantCalls: []string{http.MethodGet, http.MethodDelete},
+			wantPools: []string{"pool-a", "pool-b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pools := []string{"pool-a", "pool-b"}
+			var calls []string
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				"/rest/inventory/v1/global-desktop-entitlements/gde/local-desktop-pools": testLocalPoolsHandler(t, &pools, &calls, tc.failing),
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceGlobalDesktopEntitlement().Schema, map[string]interface{}{
+				"local_desktop_pool_ids": []interface{}{"pool-b", "pool-c"},
+			})
+			d.SetId("gde")
+			client := testAPIClient(srv).Client
+
+			diags := resourceGlobalDesktopEntitlementUpdatePools(context.Background(), d, &client)
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(calls, tc.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tc.wantCalls)
+			}
+			if !reflect.DeepEqual(pools, tc.wantPools) {
+				t.Errorf("local pools = %v, want %v", pools, tc.wantPools)
+			}
+		})
+	}
+}
+
+func TestResourceGlobalDesktopEntitlementDeleteRemovesPools(t *testing.T) {
+	pools := []string{"pool-a", "pool-b"}
+	var calls []string
+	deleted := false
+	srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+		"/rest/inventory/v1/global-desktop-entitlements/gde/local-desktop-pools": testLocalPoolsHandler(t, &pools, &calls, nil),
+		"/rest/inventory/v1/global-desktop-entitlements/gde": func(w http.ResponseWriter, r *http.Request) {
+			if len(pools) != 0 {
+				t.Errorf("global desktop entitlement deleted while it still has local pools %v", pools)
+			}
+			deleted = true
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceGlobalDesktopEntitlement().Schema, map[string]interface{}{})
+	d.SetId("gde")
+
+	if diags := resourceGlobalDesktopEntitlementDelete(context.Background(), d, testAPIClient(srv)); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %#v", diags)
+	}
+	if !deleted {
+		t.Errorf("global desktop entitlement was not deleted")
+	}
+}
+
+func TestResourceGlobalDesktopEntitlementRead(t *testing.T) {
+	testResourceRead(t, resourceGlobalDesktopEntitlement(), "gde", "/rest/inventory/v2/global-desktop-entitlements/gde", map[string]http.HandlerFunc{
+		"/rest/inventory/v1/global-desktop-entitlements/gde/local-desktop-pools": func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, []string{"pool-a", "pool-b"})
+		},
+	}, []testReadCase{
+		{
+			name:   "deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:   "global desktop entitlement",
+			object: map[string]interface{}{"id": "gde", "name": "desktops", "scope": "ANY"},
+			wantID: "gde",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got, want := sortedStringSet(d.Get("local_desktop_pool_ids").(*schema.Set)), []string{"pool-a", "pool-b"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("local_desktop_pool_ids = %v, want %v", got, want)
+				}
+			},
+		},
+	})
+}
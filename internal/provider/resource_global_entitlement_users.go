@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/umich-vci/gohorizon"
+)
+
+func resourceGlobalEntitlementUsers() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for managing the AD users and groups entitled to a Global Desktop Entitlement or Global Application Entitlement. The ID of this resource is in the form `<entitlement_type>/<global_entitlement_id>`, for example `DESKTOP/<global_entitlement_id>`, which is also the format used to import it.",
+
+		CreateContext: resourceGlobalEntitlementUsersCreate,
+		ReadContext:   resourceGlobalEntitlementUsersRead,
+		UpdateContext: resourceGlobalEntitlementUsersUpdate,
+		DeleteContext: resourceGlobalEntitlementUsersDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"ad_user_or_group_ids": {
+				Description: "List of ad-user-or-group SIDs for the entitlement operations on the given global entitlement.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"entitlement_type": {
+				Description:  "Type of the global entitlement. DESKTOP: Global Desktop Entitlement. APPLICATION: Global Application Entitlement.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"DESKTOP", "APPLICATION"}, false),
+			},
+			"global_entitlement_id": {
+				Description: "Unique ID representing the global entitlement.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+// parseGlobalEntitlementUsersID splits a horizon_global_entitlement_users ID into the entitlement type and the global entitlement ID.
+func parseGlobalEntitlementUsersID(id string) (string, string, diag.Diagnostics) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[1] == "" || (parts[0] != "DESKTOP" && parts[0] != "APPLICATION") {
+		return "", "", diag.Errorf("unexpected ID %q, expected <entitlement_type>/<global_entitlement_id> where entitlement_type is DESKTOP or APPLICATION", id)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func getGlobalEntitlementUsers(ctx context.Context, client *gohorizon.APIClient, entitlementType, id string) (gohorizon.EntitlementInfo, *http.Response, error) {
+	if entitlementType == "APPLICATION" {
+		return client.EntitlementsApi.GetGAEEntitlement(ctx, id).Execute()
+	}
+	return client.EntitlementsApi.GetGDEEntitlement(ctx, id).Execute()
+}
+
+func createGlobalEntitlementUsers(ctx context.Context, client *gohorizon.APIClient, entitlementType, id string, adIDs []string) diag.Diagnostics {
+	bodyElem := gohorizon.NewEntitlementSpec()
+	bodyElem.Id = &id
+	bodyElem.AdUserOrGroupIds = &adIDs
+	body := []gohorizon.EntitlementSpec{*bodyElem}
+
+	var results []gohorizon.BulkEntitlementResponseInfo
+	var resp *http.Response
+	var err error
+	if entitlementType == "APPLICATION" {
+		results, resp, err = client.EntitlementsApi.BulkCreateGAEEntitlements(ctx).Body(body).Execute()
+	} else {
+		results, resp, err = client.EntitlementsApi.BulkCreateGDEEntitlements(ctx).Body(body).Execute()
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
+	}
+
+	return nil
+}
+
+func deleteGlobalEntitlementUsers(ctx context.Context, client *gohorizon.APIClient, entitlementType, id string, adIDs []string) diag.Diagnostics {
+	bodyElem := gohorizon.NewEntitlementSpec()
+	bodyElem.Id = &id
+	bodyElem.AdUserOrGroupIds = &adIDs
+	body := []gohorizon.EntitlementSpec{*bodyElem}
+
+	var results []gohorizon.BulkEntitlementResponseInfo
+	var resp *http.Response
+	var err error
+	if entitlementType == "APPLICATION" {
+		results, resp, err = client.EntitlementsApi.BulkDeleteGAEEntitlements(ctx).Body(body).Execute()
+	} else {
+		results, resp, err = client.EntitlementsApi.BulkDeleteGDEEntitlements(ctx).Body(body).Execute()
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+	if diags := bulkEntitlementResponseErrors(results); diags != nil {
+		return diags
+	}
+
+	return nil
+}
+
+func resourceGlobalEntitlementUsersCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	entitlementType := d.Get("entitlement_type").(string)
+	geID := d.Get("global_entitlement_id").(string)
+	adIDs := expandStringSet(d.Get("ad_user_or_group_ids").(*schema.Set))
+
+	if diags := createGlobalEntitlementUsers(ctx, &client, entitlementType, geID, adIDs); diags != nil {
+		return diags
+	}
+
+	d.SetId(entitlementType + "/" + geID)
+
+	return resourceGlobalEntitlementUsersRead(ctx, d, meta)
+}
+
+func resourceGlobalEntitlementUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	entitlementType, geID, diags := parseGlobalEntitlementUsersID(d.Id())
+	if diags != nil {
+		return diags
+	}
+
+	entitlement, resp, err := getGlobalEntitlementUsers(ctx, &client, entitlementType, geID)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		// the global entitlement was deleted outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	d.Set("entitlement_type", entitlementType)
+	d.Set("global_entitlement_id", geID)
+	d.Set("ad_user_or_group_ids", entitlement.AdUserOrGroupIds)
+
+	return nil
+}
+
+func resourceGlobalEntitlementUsersUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	entitlementType, geID, diags := parseGlobalEntitlementUsersID(d.Id())
+	if diags != nil {
+		return diags
+	}
+	adIDs := expandStringSet(d.Get("ad_user_or_group_ids").(*schema.Set))
+
+	currentADIDs, resp, err := getGlobalEntitlementUsers(ctx, &client, entitlementType, geID)
+	if err != nil {
+		return returnResponseErr(resp, err)
+	}
+
+	addList, removeList := diffStringSets(currentADIDs.GetAdUserOrGroupIds(), adIDs)
+
+	if len(removeList) > 0 {
+		if diags := deleteGlobalEntitlementUsers(ctx, &client, entitlementType, geID, removeList); diags != nil {
+			return diags
+		}
+	}
+
+	if len(addList) > 0 {
+		if diags := createGlobalEntitlementUsers(ctx, &client, entitlementType, geID, addList); diags != nil {
+			return diags
+		}
+	}
+
+	return resourceGlobalEntitlementUsersRead(ctx, d, meta)
+}
+
+func resourceGlobalEntitlementUsersDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).Client
+
+	entitlementType := d.Get("entitlement_type").(string)
+	geID := d.Get("global_entitlement_id").(string)
+	adIDs := expandStringSet(d.Get("ad_user_or_group_ids").(*schema.Set))
+
+	return deleteGlobalEntitlementUsers(ctx, &client, entitlementType, geID, adIDs)
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseGlobalEntitlementUsersID(t *testing.T) {
+	cases := []struct {
+		id       string
+		wantType string
+		wantID   string
+		wantErr  bool
+	}{
+		{"DESKTOP/gde", "DESKTOP", "gde", false},
+		{"APPLICATION/gae", "APPLICATION", "gae", false},
+		{"APPLICATION/gae/extra", "APPLICATION", "gae/extra", false},
+		{"gde", "", "", true},
+		{"DESKTOP/", "", "", true},
+		{"POOL/gde", "", "", true},
+		{"desktop/gde", "", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.id, func(t *testing.T) {
+			entitlementType, id, diags := parseGlobalEntitlementUsersID(tc.id)
+			if diags.HasError() != tc.wantErr {
+				t.Fatalf("parseGlobalEntitlementUsersID(%q) diagnostics = %#v, want error %t", tc.id, diags, tc.wantErr)
+			}
+			if entitlementType != tc.wantType || id != tc.wantID {
+				t.Errorf("parseGlobalEntitlementUsersID(%q) = %q, %q, want %q, %q", tc.id, entitlementType, id, tc.wantType, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestResourceGlobalEntitlementUsersUpdate(t *testing.T) {
+	cases := []struct {
+		name            string
+		entitlementType string
+		path            string
+		failing         map[string]bool
+		wantError       string
+		wantEntitled    []string
+	}{
+		{
+			name:            "global desktop entitlement",
+			entitlementType: "DESKTOP",
+			path:            "/rest/entitlements/v1/global-desktop-entitlements",
+			wantEntitled:    []string{"S-1-5-21-2", "S-1-5-21-3"},
+		},
+		{
+			name:            "global application entitlement",
+			entitlementType: "APPLICATION",
+			path:            "/rest/entitlements/v1/global-application-entitlements",
+			wantEntitled:    []string{"S-1-5-21-2", "S-1-5-21-3"},
+		},
+		{
+			name:            "failed group",
+			entitlementType: "DESKTOP",
+			path:            "/rest/entitlements/v1/global-desktop-entitlements",
+			failing:         map[string]bool{"S-1-5-21-3": true},
+			wantError:       "entitlement of S-1-5-21-3 to ge failed with status 404",
+			wantEntitled:    []string{"S-1-5-21-2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entitled := []string{"S-1-5-21-1", "S-1-5-21-2"}
+			srv := newTestHorizonServer(t, map[string]http.HandlerFunc{
+				tc.path: testEntitlementsHandler(t, &entitled, tc.failing),
+				tc.path + "/ge": func(w http.ResponseWriter, r *http.Request) {
+					writeTestJSON(w, http.StatusOK, map[string]interface{}{"id": "ge", "ad_user_or_group_ids": entitled})
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, resourceGlobalEntitlementUsers().Schema, map[string]interface{}{
+				"entitlement_type":      tc.entitlementType,
+				"global_entitlement_id": "ge",
+				"ad_user_or_group_ids":  []interface{}{"S-1-5-21-2", "S-1-5-21-3"},
+			})
+			d.SetId(tc.entitlementType + "/ge")
+
+			diags := resourceGlobalEntitlementUsersUpdate(context.Background(), d, testAPIClient(srv))
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("diagnostics = %#v, want an error containing %q", diags, tc.wantError)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %#v", diags)
+			}
+
+			if !reflect.DeepEqual(entitled, tc.wantEntitled) {
+				t.Errorf("entitled = %v, want %v", entitled, tc.wantEntitled)
+			}
+		})
+	}
+}
+
+func TestResourceGlobalEntitlementUsersRead(t *testing.T) {
+	testResourceRead(t, resourceGlobalEntitlementUsers(), "APPLICATION/ge", "/rest/entitlements/v1/global-application-entitlements/ge", nil, []testReadCase{
+		{
+			name:   "global entitlement deleted outside of Terraform",
+			wantID: "",
+		},
+		{
+			name:   "global application entitlement",
+			object: map[string]interface{}{"id": "ge", "ad_user_or_group_ids": []string{"S-1-5-21-1"}},
+			wantID: "APPLICATION/ge",
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := d.Get("entitlement_type"); got != "APPLICATION" {
+					t.Errorf("entitlement_type = %v, want APPLICATION", got)
+				}
+				if got, want := sortedStringSet(d.Get("ad_user_or_group_ids").(*schema.Set)), []string{"S-1-5-21-1"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("ad_user_or_group_ids = %v, want %v", got, want)
+				}
+			},
+		},
+	})
+}
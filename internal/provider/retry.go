@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// retryBaseWait is the wait before the first retry. It doubles on every following retry.
+const retryBaseWait = 1 * time.Second
+
+// retryTransport retries requests that failed because the Horizon Connection Server was busy or
+// briefly unreachable, using exponential backoff with jitter.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+	// attemptTimeout bounds each attempt, including reading its response body. 0 means no timeout.
+	attemptTimeout time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request can only be sent again if its body can be replayed
+	if req.Body != nil && req.GetBody == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.roundTripAttempt(attemptReq)
+		if attempt >= t.maxRetries || !retryable(req.Method, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// roundTripAttempt sends a single attempt of a request, bounded by attemptTimeout.
+func (t *retryTransport) roundTripAttempt(req *http.Request) (*http.Response, error) {
+	if t.attemptTimeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.attemptTimeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// the deadline must keep applying while the caller reads the body
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// cancelOnClose releases the context of an attempt once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+// retryable reports whether a request can be sent again after the given response or error.
+// Requests that are not idempotent are only retried when the server did not process them.
+func retryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+
+		return idempotent(method) && errors.Is(err, syscall.ECONNRESET)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns how long to wait before the next attempt, honoring a Retry-After header if the server sent one.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > t.maxWait {
+				return t.maxWait
+			}
+			return wait
+		}
+	}
+
+	wait := retryBaseWait << attempt
+	if wait > t.maxWait || wait <= 0 {
+		wait = t.maxWait
+	}
+
+	// wait somewhere between half and all of the backoff so concurrent requests spread out
+	half := int64(wait / 2)
+	if half <= 0 {
+		return wait
+	}
+
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
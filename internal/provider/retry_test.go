@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}
+	resetErr := &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	cases := []struct {
+		name   string
+		method string
+		status int
+		err    error
+		want   bool
+	}{
+		{"dial error", http.MethodPost, 0, dialErr, true},
+		{"connection reset on GET", http.MethodGet, 0, resetErr, true},
+		{"connection reset on DELETE", http.MethodDelete, 0, resetErr, true},
+		{"connection reset on POST", http.MethodPost, 0, resetErr, false},
+		{"other error", http.MethodGet, 0, errors.New("tls: bad certificate"), false},
+		{"too many requests", http.MethodPost, http.StatusTooManyRequests, nil, true},
+		{"service unavailable", http.MethodPost, http.StatusServiceUnavailable, nil, true},
+		{"internal server error", http.MethodGet, http.StatusInternalServerError, nil, false},
+		{"ok", http.MethodGet, http.StatusOK, nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp *http.Response
+			if tc.err == nil {
+				resp = &http.Response{StatusCode: tc.status}
+			}
+
+			if got := retryable(tc.method, resp, tc.err); got != tc.want {
+				t.Errorf("retryable() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  string
+		min    time.Duration
+		max    time.Duration
+		wantOK bool
+	}{
+		{"missing", "", 0, 0, false},
+		{"seconds", "5", 5 * time.Second, 5 * time.Second, true},
+		{"zero seconds", "0", 0, 0, true},
+		{"negative seconds", "-1", 0, 0, false},
+		{"garbage", "soon", 0, 0, false},
+		{"future date", time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat), 28 * time.Second, 30 * time.Second, true},
+		{"past date", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := retryAfter(tc.value)
+			if ok != tc.wantOK {
+				t.Fatalf("retryAfter(%q) ok = %t, want %t", tc.value, ok, tc.wantOK)
+			}
+			if got < tc.min || got > tc.max {
+				t.Errorf("retryAfter(%q) = %s, want between %s and %s", tc.value, got, tc.min, tc.max)
+			}
+		})
+	}
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	transport := &retryTransport{maxWait: 30 * time.Second}
+
+	cases := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		min        time.Duration
+		max        time.Duration
+	}{
+		{"first retry", 0, "", retryBaseWait / 2, retryBaseWait},
+		{"third retry", 2, "", 2 * retryBaseWait, 4 * retryBaseWait},
+		{"capped at max_retry_wait", 10, "", 15 * time.Second, 30 * time.Second},
+		{"shift overflow", 100, "", 15 * time.Second, 30 * time.Second},
+		{"Retry-After", 0, "7", 7 * time.Second, 7 * time.Second},
+		{"Retry-After capped at max_retry_wait", 0, "3600", 30 * time.Second, 30 * time.Second},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			for i := 0; i < 20; i++ {
+				if got := transport.backoff(tc.attempt, resp); got < tc.min || got > tc.max {
+					t.Fatalf("backoff() = %s, want between %s and %s", got, tc.min, tc.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	cases := []struct {
+		name       string
+		method     string
+		body       func() io.Reader
+		failures   []int
+		maxRetries int
+		wantStatus int
+		wantCalls  int32
+	}{
+		{
+			name:       "retries service unavailable",
+			method:     http.MethodGet,
+			body:       func() io.Reader { return nil },
+			failures:   []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			maxRetries: 5,
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "retries too many requests with body",
+			method:     http.MethodPost,
+			body:       func() io.Reader { return strings.NewReader("payload") },
+			failures:   []int{http.StatusTooManyRequests},
+			maxRetries: 5,
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "gives up after max_retries",
+			method:     http.MethodGet,
+			body:       func() io.Reader { return nil },
+			failures:   []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			maxRetries: 2,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  3,
+		},
+		{
+			name:       "does not retry other errors",
+			method:     http.MethodGet,
+			body:       func() io.Reader { return nil },
+			failures:   []int{http.StatusInternalServerError},
+			maxRetries: 5,
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+		{
+			name:       "does not retry a body that can not be replayed",
+			method:     http.MethodPost,
+			body:       func() io.Reader { return io.NopCloser(strings.NewReader("payload")) },
+			failures:   []int{http.StatusServiceUnavailable},
+			maxRetries: 5,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				if body, _ := io.ReadAll(r.Body); tc.method == http.MethodPost && string(body) != "payload" {
+					t.Errorf("request body = %q, want %q", body, "payload")
+				}
+				if int(call) <= len(tc.failures) {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tc.failures[call-1])
+				}
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: &retryTransport{
+				base:       http.DefaultTransport,
+				maxRetries: tc.maxRetries,
+				maxWait:    10 * time.Millisecond,
+			}}
+
+			req, err := http.NewRequest(tc.method, srv.URL, tc.body())
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %s", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("server called %d times, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportAttemptTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		base:           http.DefaultTransport,
+		maxRetries:     0,
+		maxWait:        10 * time.Millisecond,
+		attemptTimeout: 100 * time.Millisecond,
+	}}
+
+	if _, err := client.Get(srv.URL + "/slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("slow request error = %v, want a deadline exceeded error", err)
+	}
+
+	// the timeout must not cut off a response body that is read after the attempt returned
+	resp, err := client.Get(srv.URL + "/fast")
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "body" {
+		t.Errorf("body = %q, %v, want %q", body, err, "body")
+	}
+}
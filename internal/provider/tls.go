@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// tlsVersions maps the values accepted by min_tls_version to their crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig builds the TLS configuration used to connect to the VMware Horizon server from the provider settings.
+func newTLSConfig(d *schema.ResourceData) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: !d.Get("ssl_verify").(bool),
+		ServerName:         d.Get("tls_server_name").(string),
+		MinVersion:         tlsVersions[d.Get("min_tls_version").(string)],
+	}
+
+	caFile := d.Get("ca_file").(string)
+	caPEM := d.Get("ca_pem").(string)
+	if caFile != "" && caPEM != "" {
+		return nil, fmt.Errorf("only one of ca_file and ca_pem can be set")
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_file: %w", err)
+		}
+		caPEM = string(pem)
+	}
+	if caPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in the CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	clientCert := d.Get("client_cert_pem").(string)
+	clientKey := d.Get("client_key_pem").(string)
+	if (clientCert == "") != (clientKey == "") {
+		return nil, fmt.Errorf("client_cert_pem and client_key_pem must be set together")
+	}
+	if clientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
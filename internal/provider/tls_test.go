@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testCertificate returns a self-signed certificate and its private key, both PEM encoded.
+func testCertificate(t *testing.T) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating the key: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "horizon.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating the certificate: %s", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("error encoding the key: %s", err)
+	}
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	return certPEM, keyPEM
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	certPEM, keyPEM := testCertificate(t)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(certPEM), 0600); err != nil {
+		t.Fatalf("error writing the CA bundle: %s", err)
+	}
+
+	cases := []struct {
+		name      string
+		raw       map[string]interface{}
+		wantError string
+		check     func(t *testing.T, tlsConfig *tls.Config)
+	}{
+		{
+			name: "defaults",
+			raw:  map[string]interface{}{},
+			check: func(t *testing.T, tlsConfig *tls.Config) {
+				if tlsConfig.InsecureSkipVerify {
+					t.Errorf("InsecureSkipVerify = true, want certificates to be verified by default")
+				}
+				if tlsConfig.MinVersion != tls.VersionTLS12 {
+					t.Errorf("MinVersion = %x, want TLS 1.2", tlsConfig.MinVersion)
+				}
+				if tlsConfig.RootCAs != nil {
+					t.Errorf("RootCAs is set, want the system pool")
+				}
+			},
+		},
+		{
+			name: "TLS 1.3 without verification",
+			raw:  map[string]interface{}{"min_tls_version": "1.3", "ssl_verify": false},
+			check: func(t *testing.T, tlsConfig *tls.Config) {
+				if !tlsConfig.InsecureSkipVerify {
+					t.Errorf("InsecureSkipVerify = false, want true when ssl_verify is false")
+				}
+				if tlsConfig.MinVersion != tls.VersionTLS13 {
+					t.Errorf("MinVersion = %x, want TLS 1.3", tlsConfig.MinVersion)
+				}
+			},
+		},
+		{
+			name: "server name",
+			raw:  map[string]interface{}{"tls_server_name": "horizon.example.com"},
+			check: func(t *testing.T, tlsConfig *tls.Config) {
+				if tlsConfig.ServerName != "horizon.example.com" {
+					t.Errorf("ServerName = %q, want %q", tlsConfig.ServerName, "horizon.example.com")
+				}
+			},
+		},
+		{
+			name: "CA bundle file",
+			raw:  map[string]interface{}{"ca_file": caFile},
+			check: func(t *testing.T, tlsConfig *tls.Config) {
+				if tlsConfig.RootCAs == nil {
+					t.Errorf("RootCAs is not set, want the CA bundle of ca_file")
+				}
+			},
+		},
+		{
+			name: "inline CA bundle",
+			raw:  map[string]interface{}{"ca_pem": certPEM},
+			check: func(t *testing.T, tlsConfig *tls.Config) {
+				if tlsConfig.RootCAs == nil {
+					t.Errorf("RootCAs is not set, want the CA bundle of ca_pem")
+				}
+			},
+		},
+		{
+			name:      "CA bundle file and inline CA bundle",
+			raw:       map[string]interface{}{"ca_file": caFile, "ca_pem": certPEM},
+			wantError: "only one of ca_file and ca_pem can be set",
+		},
+		{
+			name:      "missing CA bundle file",
+			raw:       map[string]interface{}{"ca_file": filepath.Join(t.TempDir(), "missing.pem")},
+			wantError: "error reading ca_file",
+		},
+		{
+			name:      "invalid CA bundle",
+			raw:       map[string]interface{}{"ca_pem": "not a certificate"},
+			wantError: "no PEM encoded certificates found",
+		},
+		{
+			name: "client certificate",
+			raw:  map[string]interface{}{"client_cert_pem": certPEM, "client_key_pem": keyPEM},
+			check: func(t *testing.T, tlsConfig *tls.Config) {
+				if len(tlsConfig.Certificates) != 1 {
+					t.Errorf("Certificates = %d, want the client certificate", len(tlsConfig.Certificates))
+				}
+			},
+		},
+		{
+			name:      "client certificate without key",
+			raw:       map[string]interface{}{"client_cert_pem": certPEM},
+			wantError: "client_cert_pem and client_key_pem must be set together",
+		},
+		{
+			name:      "client key without certificate",
+			raw:       map[string]interface{}{"client_key_pem": keyPEM},
+			wantError: "client_cert_pem and client_key_pem must be set together",
+		},
+		{
+			name:      "invalid client certificate",
+			raw:       map[string]interface{}{"client_cert_pem": "not a certificate", "client_key_pem": keyPEM},
+			wantError: "error loading client certificate",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, New("dev")().Schema, tc.raw)
+
+			tlsConfig, err := newTLSConfig(d)
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("error = %v, want an error containing %q", err, tc.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			tc.check(t, tlsConfig)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newTransport builds the transport used for every connection to the VMware Horizon server from the
+// TLS, proxy and connection settings of the provider.
+func newTransport(d *schema.ResourceData) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// start from the default transport so the dial and handshake timeouts and HTTP/2 support are kept
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.MaxIdleConns = d.Get("max_idle_conns").(int)
+	transport.MaxIdleConnsPerHost = d.Get("max_idle_conns_per_host").(int)
+	transport.IdleConnTimeout = time.Duration(d.Get("idle_conn_timeout").(int)) * time.Second
+
+	// without an explicit proxy, HTTPS_PROXY and NO_PROXY are honored by the default transport
+	if proxyURL := d.Get("proxy_url").(string); proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing proxy_url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return transport, nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/umich-vci/terraform-provider-horizon/internal/provider"
@@ -42,4 +44,10 @@ func main() {
 	}
 
 	plugin.Serve(opts)
+
+	// Serve returns once Terraform is done with the provider. Logging out is best-effort, an
+	// unreachable connection server must not keep the provider from exiting.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	provider.Logout(ctx)
 }